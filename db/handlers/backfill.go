@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"db/database"
+	"log"
+)
+
+// BackfillReport は検索用の情報を作成したアイテムの件数
+type BackfillReport struct {
+	Snippets     int `json:"snippets"`
+	Fingerprints int `json:"fingerprints"`
+}
+
+// BackfillItemIndexes は検索用の情報が保存される前に作成されたアイテムについて、その情報を作成する
+// 作成済みのアイテムは対象にしないため、起動のたびに呼び出しても作成は一度だけ行われる
+func BackfillItemIndexes() (BackfillReport, error) {
+	var report BackfillReport
+	n, err := backfillSnippets()
+	report.Snippets = n
+	if err != nil {
+		return report, err
+	}
+	n, err = backfillFingerprints()
+	report.Fingerprints = n
+	return report, err
+}
+
+// backfillSnippets はコードブロックの保存されていないアイテムのコードブロックを保存する
+// 言語で検索できるのはフェンスで囲んだコードブロックのみのため、フェンスを含むアイテムだけを対象にする
+func backfillSnippets() (int, error) {
+	rows, err := database.Db.Query("SELECT id, content FROM items" +
+		" WHERE (content LIKE '%```%' OR content LIKE '%~~~%')" +
+		" AND id NOT IN (SELECT itemId FROM item_snippets)")
+	if err != nil {
+		return 0, err
+	}
+	type pending struct{ id, content string }
+	var items []pending
+	for rows.Next() {
+		var p pending
+		if err := rows.Scan(&p.id, &p.content); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		items = append(items, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, p := range items {
+		if err := saveItemSnippets(p.id, p.content); err != nil {
+			log.Printf("Error: failed to save code snippets for %s: %v\n", p.id, err)
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
+// backfillFingerprints は重複判定用のハッシュが保存されていないアイテムのハッシュを保存する
+func backfillFingerprints() (int, error) {
+	rows, err := database.Db.Query("SELECT id, title, content, file FROM items WHERE id NOT IN (SELECT itemId FROM item_fingerprints)")
+	if err != nil {
+		return 0, err
+	}
+	type pending struct{ id, title, content, file string }
+	var items []pending
+	for rows.Next() {
+		var p pending
+		if err := rows.Scan(&p.id, &p.title, &p.content, &p.file); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		items = append(items, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, p := range items {
+		fingerprint, err := computeFingerprint(p.title, p.content, p.file)
+		if err == nil {
+			err = saveFingerprint(p.id, fingerprint)
+		}
+		if err != nil {
+			log.Printf("Error: failed to save fingerprint for %s: %v\n", p.id, err)
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
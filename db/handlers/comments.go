@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"database/sql"
+	"db/database"
+	"db/model"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// commentColumns は comments テーブルから読み込む列（scanComment の Scan と順番を合わせる）
+const commentColumns = "id, itemId, COALESCE(parentId, ''), body, createdBy, createdByName, createdAt, updatedAt, deleted"
+
+// 1ページあたりのコメント数の既定値と上限
+const (
+	defaultCommentPageSize = 20
+	maxCommentPageSize     = 100
+)
+
+// scanComment は commentColumns の順に読み込んだ行を model.Comment に変換する
+func scanComment(s scanner) (model.Comment, error) {
+	var c model.Comment
+	var createdAtStr, updatedAtStr string
+	if err := s.Scan(&c.ID, &c.ItemID, &c.ParentID, &c.Body, &c.CreatedBy, &c.CreatedByName, &createdAtStr, &updatedAtStr, &c.Deleted); err != nil {
+		return c, err
+	}
+	var err error
+	if c.CreatedAt, err = time.Parse("2006-01-02 15:04:05", createdAtStr); err != nil {
+		return c, err
+	}
+	if c.UpdatedAt, err = time.Parse("2006-01-02 15:04:05", updatedAtStr); err != nil {
+		return c, err
+	}
+	return c, nil
+}
+
+// findComment はIDを指定してコメントを1件取得する
+func findComment(id string) (model.Comment, error) {
+	return scanComment(database.Db.QueryRow("SELECT "+commentColumns+" FROM comments WHERE id = ?", id))
+}
+
+// queryComments はコメントを検索してスライスで返す
+func queryComments(query string, params ...interface{}) ([]model.Comment, error) {
+	rows, err := database.Db.Query(query, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []model.Comment{}
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, c)
+	}
+	return comments, rows.Err()
+}
+
+// listComments はアイテムのトップレベルのコメントを古い順にページ単位で取得し、返信を入れ子にして返す
+func listComments(itemID string, page, pageSize int) ([]model.Comment, int, error) {
+	var total int
+	if err := database.Db.QueryRow("SELECT COUNT(*) FROM comments WHERE itemId = ? AND parentId IS NULL", itemID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	comments, err := queryComments("SELECT "+commentColumns+" FROM comments WHERE itemId = ? AND parentId IS NULL ORDER BY createdAt, id LIMIT ? OFFSET ?",
+		itemID, pageSize, (page-1)*pageSize)
+	if err != nil || len(comments) == 0 {
+		return comments, total, err
+	}
+
+	index := make(map[string]int, len(comments))
+	params := make([]interface{}, 0, len(comments))
+	for i, c := range comments {
+		index[c.ID] = i
+		params = append(params, c.ID)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(comments)), ", ")
+	replies, err := queryComments("SELECT "+commentColumns+" FROM comments WHERE parentId IN ("+placeholders+") ORDER BY createdAt, id", params...)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, reply := range replies {
+		parent := &comments[index[reply.ParentID]]
+		parent.Replies = append(parent.Replies, reply)
+	}
+	return comments, total, nil
+}
+
+// findOwnComment は呼び出したユーザーが変更できるコメントを取得する
+// 編集できるのは投稿者のみ、allowStaff が true の場合（削除）はアイテムと同じくコースの講師も対象にする
+func findOwnComment(w http.ResponseWriter, commentID, userEmail string, allowStaff bool) (model.Comment, bool) {
+	comment, err := findComment(commentID)
+	if err == sql.ErrNoRows {
+		logAndSendError(w, "Comment not found", http.StatusNotFound, err)
+		return comment, false
+	}
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return comment, false
+	}
+	if userEmail != "" && comment.CreatedBy == userEmail {
+		return comment, true
+	}
+	if allowStaff {
+		item, err := findItem(comment.ItemID)
+		if err != nil && err != sql.ErrNoRows {
+			logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+			return comment, false
+		}
+		if err == nil {
+			staff, err := isCourseStaff(item.CourseID, userEmail)
+			if err != nil {
+				logAndSendError(w, "Failed to check course membership", http.StatusInternalServerError, err)
+				return comment, false
+			}
+			if staff {
+				return comment, true
+			}
+		}
+		logAndSendError(w, "Only the author or course instructors can delete this comment", http.StatusForbidden, nil)
+		return comment, false
+	}
+	logAndSendError(w, "Only the author can change this comment", http.StatusForbidden, nil)
+	return comment, false
+}
+
+// deleteComment はコメントを削除する
+// 返信があるコメントは返信を残すため本文を消して削除済みにし、返信がなければ行ごと削除する
+// 削除済みの親コメントの最後の返信を削除した場合は、親コメントも削除する
+func deleteComment(comment model.Comment) error {
+	tx, err := database.Db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// 同じスレッドへの返信・削除と直列化する
+	threadID := comment.ID
+	if comment.ParentID != "" {
+		threadID = comment.ParentID
+	}
+	if _, err := tx.Exec("SELECT id FROM comments WHERE id = ? FOR UPDATE", threadID); err != nil {
+		return err
+	}
+
+	var replies int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM comments WHERE parentId = ?", comment.ID).Scan(&replies); err != nil {
+		return err
+	}
+	query := "DELETE FROM comments WHERE id = ?"
+	if replies > 0 {
+		query = "UPDATE comments SET body = '', deleted = TRUE, updatedAt = NOW() WHERE id = ?"
+	}
+	if _, err := tx.Exec(query, comment.ID); err != nil {
+		return err
+	}
+
+	if comment.ParentID != "" {
+		_, err := tx.Exec("DELETE FROM comments WHERE id = ? AND deleted = TRUE AND NOT EXISTS (SELECT 1 FROM (SELECT id FROM comments WHERE parentId = ?) AS replies)",
+			comment.ParentID, comment.ParentID)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"db/markdown"
+	"db/model"
+)
+
+// renderContent はアイテムの本文（Markdown）を安全な HTML と目次に変換して設定する
+// 本文中のアイテムの添付ファイルへのリンクは署名付きURLに書き換える
+// 他のファイルのキーは、閲覧できないファイルのURLを発行しないよう書き換えない
+func renderContent(item *model.Item) {
+	result := markdown.Render(item.Content, markdown.Options{RewriteURL: func(u string) string {
+		if isStoredFile(u) && u == item.File {
+			return fileURL(u)
+		}
+		return u
+	}})
+	item.ContentHTML = result.HTML
+	item.TOC = make([]model.Heading, len(result.TOC))
+	for i, h := range result.TOC {
+		item.TOC[i] = model.Heading(h)
+	}
+}
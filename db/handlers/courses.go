@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"database/sql"
+	"db/database"
+	"net/http"
+)
+
+// コース内での役割
+const (
+	courseRoleOwner      = "owner"      // コースの作成者
+	courseRoleInstructor = "instructor" // カテゴリ・章の管理とメンバーの追加ができる
+	courseRoleStudent    = "student"    // 一般の受講者
+)
+
+// isValidCourseRole は役割の値が正しいかどうかを判定する
+func isValidCourseRole(role string) bool {
+	switch role {
+	case courseRoleOwner, courseRoleInstructor, courseRoleStudent:
+		return true
+	}
+	return false
+}
+
+// courseRole はユーザーのコース内での役割を返す（メンバーでない場合は空文字）
+func courseRole(courseID, userEmail string) (string, error) {
+	if courseID == "" || userEmail == "" {
+		return "", nil
+	}
+	var role string
+	err := database.Db.QueryRow("SELECT role FROM course_members WHERE courseId = ? AND userEmail = ?", courseID, userEmail).Scan(&role)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return role, err
+}
+
+// isCourseMember はユーザーがコースのメンバーかどうかを判定する
+func isCourseMember(courseID, userEmail string) (bool, error) {
+	role, err := courseRole(courseID, userEmail)
+	return role != "", err
+}
+
+// isCourseStaff はユーザーがコースの作成者か講師かを判定する
+func isCourseStaff(courseID, userEmail string) (bool, error) {
+	role, err := courseRole(courseID, userEmail)
+	return role == courseRoleOwner || role == courseRoleInstructor, err
+}
+
+// requireCourseMember はユーザーがコースのメンバーでない場合にエラーレスポンスを返して false を返す
+func requireCourseMember(w http.ResponseWriter, courseID, userEmail string) bool {
+	if courseID == "" {
+		logAndSendError(w, "courseId is required", http.StatusBadRequest, nil)
+		return false
+	}
+	member, err := isCourseMember(courseID, userEmail)
+	if err != nil {
+		logAndSendError(w, "Failed to check course membership", http.StatusInternalServerError, err)
+		return false
+	}
+	if !member {
+		logAndSendError(w, "You are not a member of this course", http.StatusForbidden, nil)
+		return false
+	}
+	return true
+}
+
+// requireCourseStaff はユーザーがコースの作成者・講師でない場合にエラーレスポンスを返して false を返す
+func requireCourseStaff(w http.ResponseWriter, courseID, userEmail string) bool {
+	if courseID == "" {
+		logAndSendError(w, "courseId is required", http.StatusBadRequest, nil)
+		return false
+	}
+	staff, err := isCourseStaff(courseID, userEmail)
+	if err != nil {
+		logAndSendError(w, "Failed to check course membership", http.StatusInternalServerError, err)
+		return false
+	}
+	if !staff {
+		logAndSendError(w, "Only course instructors can do this", http.StatusForbidden, nil)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"db/database"
+	"db/storage"
+	"db/textindex"
+	"encoding/hex"
+	"io"
+	"os"
+	"strings"
+)
+
+// 重複の判定に使う値
+const (
+	// minFingerprintTokens より短い本文は、たまたま似てしまうためsimhashで判定しない
+	minFingerprintTokens = 20
+	// maxSimhashDistance 以下のハミング距離のsimhashは同じ内容とみなす
+	maxSimhashDistance = 3
+)
+
+// 重複が見つかった場合の扱い（環境変数 DUPLICATE_POLICY、省略時は warn）
+const (
+	duplicatePolicyWarn   = "warn"   // 追加して、重複の候補をレスポンスに含める
+	duplicatePolicyReject = "reject" // 追加せず 409 を返す（force を指定した場合は追加する）
+)
+
+func duplicatePolicy() string {
+	if strings.TrimSpace(os.Getenv("DUPLICATE_POLICY")) == duplicatePolicyReject {
+		return duplicatePolicyReject
+	}
+	return duplicatePolicyWarn
+}
+
+// itemFingerprint はアイテムの重複を判定するための本文のsimhashと添付ファイルのハッシュ
+type itemFingerprint struct {
+	Simhash  *uint64 // 本文が短い場合は nil
+	FileHash string  // アップロードされたファイルがない場合は空
+}
+
+// computeFingerprint はタイトル・本文のシングルのsimhashと、アップロードされたファイルの SHA-256 を求める
+func computeFingerprint(title, content, file string) (itemFingerprint, error) {
+	var fp itemFingerprint
+	tokens := textindex.Tokenize(title + "\n" + content)
+	if len(tokens) >= minFingerprintTokens {
+		hash := textindex.Simhash(textindex.Shingles(tokens, textindex.ShingleSize))
+		fp.Simhash = &hash
+	}
+
+	if isStoredFile(file) {
+		f, err := storage.Store.Open(file)
+		if err != nil {
+			return fp, err
+		}
+		defer f.Close()
+		h := sha256.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return fp, err
+		}
+		fp.FileHash = hex.EncodeToString(h.Sum(nil))
+	}
+	return fp, nil
+}
+
+// computeUpdatedFingerprint は更新後の内容のハッシュを求める
+// ファイルが差し替えられない場合は保存済みのファイルのハッシュを使い、ファイルを読み直さない
+func computeUpdatedFingerprint(itemID, title, content, file, currentFile string) (itemFingerprint, error) {
+	if file != "" {
+		return computeFingerprint(title, content, file)
+	}
+	fp, err := computeFingerprint(title, content, "")
+	if err != nil {
+		return fp, err
+	}
+	err = database.Db.QueryRow("SELECT fileHash FROM item_fingerprints WHERE itemId = ?", itemID).Scan(&fp.FileHash)
+	if err == sql.ErrNoRows {
+		// ハッシュを保存する前に作成されたアイテムはここで求める
+		existing, err := computeFingerprint("", "", currentFile)
+		fp.FileHash = existing.FileHash
+		return fp, err
+	}
+	return fp, err
+}
+
+// findDuplicates はコース内でユーザーが閲覧できるアイテムのうち、本文が近いかファイルが同じものの ID を返す
+func findDuplicates(courseID, userEmail string, fp itemFingerprint) ([]string, error) {
+	ids := []string{}
+	if fp.Simhash == nil && fp.FileHash == "" {
+		return ids, nil
+	}
+
+	var matches []string
+	var params []interface{}
+	if fp.Simhash != nil {
+		matches = append(matches, "(f.simhash IS NOT NULL AND BIT_COUNT(f.simhash ^ ?) <= ?)")
+		params = append(params, *fp.Simhash, maxSimhashDistance)
+	}
+	if fp.FileHash != "" {
+		matches = append(matches, "f.fileHash = ?")
+		params = append(params, fp.FileHash)
+	}
+
+	readableSQL, readableParams := readableItemsCondition(userEmail)
+	params = append(append([]interface{}{courseID}, params...), readableParams...)
+	rows, err := database.Db.Query("SELECT items.id FROM item_fingerprints f JOIN items ON items.id = f.itemId"+
+		" WHERE items.courseId = ? AND ("+strings.Join(matches, " OR ")+") AND "+readableSQL+
+		" ORDER BY items.createdAt LIMIT 10", params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// saveFingerprint はアイテムの重複判定用のハッシュを保存する
+func saveFingerprint(itemID string, fp itemFingerprint) error {
+	_, err := database.Db.Exec("REPLACE INTO item_fingerprints (itemId, simhash, fileHash) VALUES (?, ?, ?)", itemID, fp.Simhash, fp.FileHash)
+	return err
+}
@@ -0,0 +1,355 @@
+package handlers
+
+import (
+	"database/sql"
+	"db/database"
+	"db/model"
+	"math"
+	"strings"
+	"time"
+)
+
+// maxGeneratedFlashcards はアイテムの本文から作成するカードの上限
+const maxGeneratedFlashcards = 50
+
+// deckColumns は flashcard_decks から読み込む列（scanDeck の Scan と順番を合わせる）
+// プレースホルダは dueCount を数えるユーザー
+const deckColumns = "d.id, d.courseId, d.title, COALESCE(d.itemId, ''), d.category, d.chapter, d.createdBy, d.createdAt, d.updatedAt" +
+	", (SELECT COUNT(*) FROM flashcards c WHERE c.deckId = d.id)" +
+	", (SELECT COUNT(*) FROM flashcards c LEFT JOIN flashcard_reviews r ON r.cardId = c.id AND r.userEmail = ?" +
+	" WHERE c.deckId = d.id AND (r.dueDate IS NULL OR r.dueDate <= UTC_DATE()))"
+
+func scanDeck(s scanner) (model.FlashcardDeck, error) {
+	var d model.FlashcardDeck
+	var createdAtStr, updatedAtStr string
+	if err := s.Scan(&d.ID, &d.CourseID, &d.Title, &d.ItemID, &d.Category, &d.Chapter, &d.CreatedBy, &createdAtStr, &updatedAtStr, &d.CardCount, &d.DueCount); err != nil {
+		return d, err
+	}
+	var err error
+	if d.CreatedAt, err = time.Parse("2006-01-02 15:04:05", createdAtStr); err != nil {
+		return d, err
+	}
+	if d.UpdatedAt, err = time.Parse("2006-01-02 15:04:05", updatedAtStr); err != nil {
+		return d, err
+	}
+	return d, nil
+}
+
+// readableDecksCondition は閲覧できないアイテムに紐づくカードの束を除くためのWHERE条件
+// アイテムに紐づかない（章から作成した）カードの束はコースの全員が閲覧できる
+func readableDecksCondition(userEmail string) (string, []interface{}) {
+	readableSQL, readableParams := readableItemsCondition(userEmail)
+	return "(d.itemId IS NULL OR d.itemId IN (SELECT id FROM items WHERE " + readableSQL + "))", readableParams
+}
+
+// findDeck はコース内のカードの束を1件取得する（閲覧できない場合は sql.ErrNoRows）
+func findDeck(courseID, deckID, userEmail string) (model.FlashcardDeck, error) {
+	readableSQL, readableParams := readableDecksCondition(userEmail)
+	params := append([]interface{}{userEmail, deckID, courseID}, readableParams...)
+	return scanDeck(database.Db.QueryRow("SELECT "+deckColumns+" FROM flashcard_decks d WHERE d.id = ? AND d.courseId = ? AND "+readableSQL, params...))
+}
+
+// listDecks はコース内のカードの束を返す
+// itemID・category・chapter を指定した場合はそれに紐づくものだけを返す
+func listDecks(courseID, userEmail, itemID, category, chapter string) ([]model.FlashcardDeck, error) {
+	readableSQL, readableParams := readableDecksCondition(userEmail)
+	query := "SELECT " + deckColumns + " FROM flashcard_decks d WHERE d.courseId = ? AND " + readableSQL
+	params := append([]interface{}{userEmail, courseID}, readableParams...)
+	if itemID != "" {
+		query += " AND d.itemId = ?"
+		params = append(params, itemID)
+	}
+	if category != "" {
+		query += " AND d.category = ?"
+		params = append(params, category)
+	}
+	if chapter != "" {
+		query += " AND d.chapter = ?"
+		params = append(params, chapter)
+	}
+	query += " ORDER BY d.category, d.chapter, d.title"
+
+	rows, err := database.Db.Query(query, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	decks := []model.FlashcardDeck{}
+	for rows.Next() {
+		d, err := scanDeck(rows)
+		if err != nil {
+			return nil, err
+		}
+		decks = append(decks, d)
+	}
+	return decks, rows.Err()
+}
+
+// queryFlashcards はカードを呼び出したユーザーの復習の状態付きで返す
+// プレースホルダの先頭はユーザー
+func queryFlashcards(where string, params ...interface{}) ([]model.Flashcard, error) {
+	rows, err := database.Db.Query(`
+		SELECT c.id, c.deckId, c.front, c.back, DATE_FORMAT(r.dueDate, '%Y-%m-%d'), COALESCE(r.repetitions, 0), COALESCE(r.intervalDays, 0)
+		FROM flashcards c
+		JOIN flashcard_decks d ON d.id = c.deckId
+		LEFT JOIN flashcard_reviews r ON r.cardId = c.id AND r.userEmail = ?
+		WHERE `+where, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cards := []model.Flashcard{}
+	for rows.Next() {
+		var c model.Flashcard
+		var dueDate sql.NullString
+		if err := rows.Scan(&c.ID, &c.DeckID, &c.Front, &c.Back, &dueDate, &c.Repetitions, &c.IntervalDays); err != nil {
+			return nil, err
+		}
+		if dueDate.Valid {
+			c.DueDate = &dueDate.String
+		}
+		cards = append(cards, c)
+	}
+	return cards, rows.Err()
+}
+
+// deckCards は束のカードを順番どおりに返す
+func deckCards(deckID, userEmail string) ([]model.Flashcard, error) {
+	return queryFlashcards("c.deckId = ? ORDER BY c.position", userEmail, deckID)
+}
+
+// dueFlashcards はコース内でユーザーが今日復習するカードを返す（期限切れのものを先に、未復習のものを後に並べる）
+func dueFlashcards(courseID, userEmail, deckID string, limit int) ([]model.Flashcard, error) {
+	readableSQL, readableParams := readableDecksCondition(userEmail)
+	where := "d.courseId = ? AND " + readableSQL + " AND (r.dueDate IS NULL OR r.dueDate <= UTC_DATE())"
+	params := append([]interface{}{userEmail, courseID}, readableParams...)
+	if deckID != "" {
+		where += " AND d.id = ?"
+		params = append(params, deckID)
+	}
+	where += " ORDER BY r.dueDate IS NULL, r.dueDate, d.id, c.position LIMIT ?"
+	return queryFlashcards(where, append(params, limit)...)
+}
+
+// saveFlashcards は束のカードを指定した順番で置き換える（空のカードは保存しない）
+// 束にすでにあるIDのカードは、復習の状態を残すためIDを保ったまま更新する
+func saveFlashcards(tx *sql.Tx, deckID string, cards []model.Flashcard) error {
+	rows, err := tx.Query("SELECT id FROM flashcards WHERE deckId = ?", deckID)
+	if err != nil {
+		return err
+	}
+	existing := map[string]bool{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		existing[id] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	kept := map[string]bool{}
+	position := 0
+	for _, c := range cards {
+		if strings.TrimSpace(c.Front) == "" || strings.TrimSpace(c.Back) == "" {
+			continue
+		}
+		if !existing[c.ID] || kept[c.ID] {
+			id, err := generateULID()
+			if err != nil {
+				return err
+			}
+			c.ID = id
+		}
+		kept[c.ID] = true
+		_, err := tx.Exec(`INSERT INTO flashcards (id, deckId, position, front, back) VALUES (?, ?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE position = VALUES(position), front = VALUES(front), back = VALUES(back)`,
+			c.ID, deckID, position, c.Front, c.Back)
+		if err != nil {
+			return err
+		}
+		position++
+	}
+
+	for id := range existing {
+		if kept[id] {
+			continue
+		}
+		if _, err := tx.Exec("DELETE FROM flashcard_reviews WHERE cardId = ?", id); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE FROM flashcards WHERE id = ?", id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteDeck はカードの束と、そのカード・復習の状態を削除する
+func deleteDeck(tx *sql.Tx, deckID string) error {
+	if _, err := tx.Exec("DELETE FROM flashcard_reviews WHERE cardId IN (SELECT id FROM flashcards WHERE deckId = ?)", deckID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM flashcards WHERE deckId = ?", deckID); err != nil {
+		return err
+	}
+	_, err := tx.Exec("DELETE FROM flashcard_decks WHERE id = ?", deckID)
+	return err
+}
+
+// deleteItemDecks はアイテムから作成したカードの束を、カード・復習の状態とあわせて削除する
+// 紐づくアイテムがないカードの束はコースの全員が閲覧できるため、アイテムの公開範囲を引き継げない束は残さない
+func deleteItemDecks(itemID string) error {
+	tx, err := database.Db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("SELECT id FROM flashcard_decks WHERE itemId = ?", itemID)
+	if err != nil {
+		return err
+	}
+	var deckIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		deckIDs = append(deckIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, id := range deckIDs {
+		if err := deleteDeck(tx, id); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// flashcardsFromContent はアイテムの本文からカードを作成する
+// 「用語: 説明」「用語：説明」の行と、見出しとその直後の段落をカードにする
+func flashcardsFromContent(content string) []model.Flashcard {
+	cards := []model.Flashcard{}
+	var heading string
+	var paragraph []string
+	flush := func() {
+		if heading != "" && len(paragraph) > 0 {
+			cards = append(cards, model.Flashcard{Front: heading, Back: strings.Join(paragraph, "\n")})
+		}
+		heading, paragraph = "", nil
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "#"):
+			flush()
+			heading = strings.TrimSpace(strings.TrimLeft(line, "#"))
+		case line == "":
+			flush()
+		case heading != "":
+			paragraph = append(paragraph, line)
+		default:
+			term, definition, ok := strings.Cut(line, "：")
+			if !ok {
+				term, definition, ok = strings.Cut(line, ": ")
+			}
+			term = strings.TrimSpace(strings.TrimLeft(term, "-*・ "))
+			definition = strings.TrimSpace(definition)
+			if ok && term != "" && definition != "" {
+				cards = append(cards, model.Flashcard{Front: term, Back: definition})
+			}
+		}
+	}
+	flush()
+
+	if len(cards) > maxGeneratedFlashcards {
+		cards = cards[:maxGeneratedFlashcards]
+	}
+	return cards
+}
+
+// reviewState はユーザーごとのカードの復習の状態（SM-2）
+type reviewState struct {
+	Easiness     float64
+	Repetitions  int
+	IntervalDays int
+	DueDate      time.Time
+}
+
+// newReviewState は未復習のカードの状態
+func newReviewState() reviewState {
+	return reviewState{Easiness: 2.5}
+}
+
+// nextReview は SM-2 に従い、思い出せた度合い（0〜5）から次の復習日を決める
+// 3 未満は思い出せなかったものとして翌日からやり直す
+func nextReview(s reviewState, quality int, today time.Time) reviewState {
+	if quality < 3 {
+		s.Repetitions = 0
+		s.IntervalDays = 1
+	} else {
+		switch s.Repetitions {
+		case 0:
+			s.IntervalDays = 1
+		case 1:
+			s.IntervalDays = 6
+		default:
+			s.IntervalDays = int(math.Round(float64(s.IntervalDays) * s.Easiness))
+		}
+		s.Repetitions++
+	}
+
+	q := float64(5 - quality)
+	s.Easiness = math.Max(1.3, s.Easiness+0.1-q*(0.08+q*0.02))
+	s.DueDate = today.AddDate(0, 0, s.IntervalDays)
+	return s
+}
+
+// recordFlashcardReview はカードの復習結果を保存し、次の復習の状態を返す
+func recordFlashcardReview(cardID, userEmail string, quality int) (reviewState, error) {
+	tx, err := database.Db.Begin()
+	if err != nil {
+		return reviewState{}, err
+	}
+	defer tx.Rollback()
+
+	state := newReviewState()
+	err = tx.QueryRow("SELECT easiness, repetitions, intervalDays FROM flashcard_reviews WHERE userEmail = ? AND cardId = ? FOR UPDATE", userEmail, cardID).
+		Scan(&state.Easiness, &state.Repetitions, &state.IntervalDays)
+	if err != nil && err != sql.ErrNoRows {
+		return reviewState{}, err
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	state = nextReview(state, quality, today)
+	_, err = tx.Exec(`INSERT INTO flashcard_reviews (userEmail, cardId, easiness, repetitions, intervalDays, dueDate, lastQuality, reviewedAt)
+		VALUES (?, ?, ?, ?, ?, ?, ?, UTC_TIMESTAMP())
+		ON DUPLICATE KEY UPDATE easiness = VALUES(easiness), repetitions = VALUES(repetitions), intervalDays = VALUES(intervalDays),
+			dueDate = VALUES(dueDate), lastQuality = VALUES(lastQuality), reviewedAt = VALUES(reviewedAt)`,
+		userEmail, cardID, state.Easiness, state.Repetitions, state.IntervalDays, state.DueDate.Format("2006-01-02"), quality)
+	if err != nil {
+		return reviewState{}, err
+	}
+	return state, tx.Commit()
+}
+
+// canEditDeck はカードの束を編集できる（作成者かコースの講師）かを判定する
+func canEditDeck(deck model.FlashcardDeck, userEmail string) (bool, error) {
+	if userEmail != "" && deck.CreatedBy == userEmail {
+		return true, nil
+	}
+	return isCourseStaff(deck.CourseID, userEmail)
+}
@@ -5,6 +5,8 @@ import (
 	"log"
 	"math/rand"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 )
 
@@ -23,3 +25,16 @@ func logAndSendError(w http.ResponseWriter, message string, status int, err erro
 	log.Printf("Error: %v\n", err)
 	http.Error(w, message, status)
 }
+
+// isAdmin は環境変数 ADMIN_EMAILS（カンマ区切り）に含まれるユーザーかどうかを判定する
+func isAdmin(email string) bool {
+	if email == "" {
+		return false
+	}
+	for _, admin := range strings.Split(os.Getenv("ADMIN_EMAILS"), ",") {
+		if strings.TrimSpace(admin) == email {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"db/database"
+	"db/storage"
+	"log"
+	"time"
+)
+
+// 削除されずに残す猶予期間の既定値
+// アップロード直後でまだアイテムに紐づいていないファイルを消さないようにする
+const DefaultGCGracePeriod = 24 * time.Hour
+
+// GCReport はガベージコレクションの結果
+type GCReport struct {
+	DryRun      bool             `json:"dryRun"`
+	GracePeriod string           `json:"gracePeriod"`
+	Scanned     int              `json:"scanned"`
+	Referenced  int              `json:"referenced"`
+	Recent      int              `json:"recent"` // 猶予期間内のため対象外にした数
+	Orphaned    []storage.Object `json:"orphaned"`
+	Deleted     int              `json:"deleted"`
+	FreedBytes  int64            `json:"freedBytes"`
+}
+
+// referencedObjects はアイテムとサムネイルから参照されているキーを集める
+// （アイテムの履歴テーブルは存在しないため、現在の値のみが対象）
+func referencedObjects() (map[string]bool, error) {
+	referenced := map[string]bool{}
+	for _, query := range []string{
+		"SELECT file FROM items WHERE file LIKE '" + storedFilePrefix + "%'",
+		"SELECT objectKey FROM thumbnails",
+	} {
+		rows, err := database.Db.Query(query)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var key string
+			if err := rows.Scan(&key); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			referenced[key] = true
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return referenced, nil
+}
+
+// CollectGarbage はどのアイテムからも参照されていないストレージ上のファイルを削除する
+// dryRun が true の場合は削除せずに対象を報告するだけにする
+func CollectGarbage(dryRun bool, grace time.Duration) (GCReport, error) {
+	report := GCReport{DryRun: dryRun, GracePeriod: grace.String(), Orphaned: []storage.Object{}}
+
+	// 参照の取得より前に一覧を取得し、その間にアップロードされたファイルを対象外にする
+	objects, err := storage.Store.List("")
+	if err != nil {
+		return report, err
+	}
+	referenced, err := referencedObjects()
+	if err != nil {
+		return report, err
+	}
+
+	cutoff := time.Now().Add(-grace)
+	for _, obj := range objects {
+		report.Scanned++
+		if referenced[obj.Key] {
+			report.Referenced++
+			continue
+		}
+		if obj.ModTime.After(cutoff) {
+			report.Recent++
+			continue
+		}
+		report.Orphaned = append(report.Orphaned, obj)
+		if dryRun {
+			continue
+		}
+		if err := storage.Store.Delete(obj.Key); err != nil {
+			log.Printf("Error: failed to delete %s: %v\n", obj.Key, err)
+			continue
+		}
+		report.Deleted++
+		report.FreedBytes += obj.Size
+	}
+	return report, nil
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"database/sql"
+	"db/database"
+	"errors"
+	"net/http"
+)
+
+var (
+	errGroupNotFound     = errors.New("group not found")
+	errInvalidVisibility = errors.New("invalid visibility")
+	errNotGroupMember    = errors.New("not a member of the group")
+)
+
+// isGroupMember はユーザーがグループのメンバーかどうかを判定する
+func isGroupMember(groupID, userEmail string) (bool, error) {
+	if groupID == "" || userEmail == "" {
+		return false, nil
+	}
+	var count int
+	err := database.Db.QueryRow("SELECT COUNT(*) FROM user_group_members WHERE groupId = ? AND userEmail = ?", groupID, userEmail).Scan(&count)
+	return count > 0, err
+}
+
+// groupOwner はグループの作成者を返す
+func groupOwner(groupID string) (string, error) {
+	var owner string
+	err := database.Db.QueryRow("SELECT createdBy FROM user_groups WHERE id = ?", groupID).Scan(&owner)
+	if err == sql.ErrNoRows {
+		return "", errGroupNotFound
+	}
+	return owner, err
+}
+
+// checkVisibility は公開範囲の指定を検証する
+// group を指定する場合、作成者自身がそのグループのメンバーである必要がある
+func checkVisibility(visibility, group, userEmail string) error {
+	if !isValidVisibility(visibility) {
+		return errInvalidVisibility
+	}
+	if visibility != visibilityGroup {
+		if group != "" {
+			return errInvalidVisibility
+		}
+		return nil
+	}
+	member, err := isGroupMember(group, userEmail)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return errNotGroupMember
+	}
+	return nil
+}
+
+// sendVisibilityError は checkVisibility のエラーをHTTPレスポンスとして返す
+func sendVisibilityError(w http.ResponseWriter, err error) {
+	switch err {
+	case errInvalidVisibility:
+		logAndSendError(w, "Invalid visibility", http.StatusBadRequest, err)
+	case errNotGroupMember:
+		logAndSendError(w, "You are not a member of the visibility group", http.StatusForbidden, err)
+	default:
+		logAndSendError(w, "Failed to check visibility", http.StatusInternalServerError, err)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"db/database"
 	"db/model"
 	"encoding/json"
+	"log"
 	"net/http"
+	"time"
 )
 
 // HandleAddItem はPOSTリクエストを処理する関数
@@ -16,12 +18,92 @@ func HandleAddItem(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// リクエストボディからデータをデコード
-	var data model.Item
+	// force は重複の候補が見つかっても追加する場合に指定する
+	var data struct {
+		model.Item
+		Force bool `json:"force"`
+	}
 	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
 		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
 		return
 	}
 
+	// コースのメンバーのみ追加でき、カテゴリと章はコースに登録されたものに限る
+	if !requireCourseMember(w, data.CourseID, data.CreatedBy) {
+		return
+	}
+	if ok, err := courseHasCategory(data.CourseID, data.Category, data.Chapter); err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	} else if !ok {
+		logAndSendError(w, "Unknown category or chapter", http.StatusBadRequest, nil)
+		return
+	}
+
+	// 公開範囲の指定がない場合は公開にする
+	if data.Visibility == "" {
+		data.Visibility = visibilityPublic
+	}
+	if err := checkVisibility(data.Visibility, data.VisibilityGroup, data.CreatedBy); err != nil {
+		sendVisibilityError(w, err)
+		return
+	}
+	var visibilityGroupValue interface{}
+	if data.VisibilityGroup != "" {
+		visibilityGroupValue = data.VisibilityGroup
+	}
+
+	// 公開状態の指定がない場合は下書きにする
+	// 公開にはレビュアーの承認が必要なため、作成時は下書きかレビュー待ちのみ指定できる
+	if data.Status == "" {
+		data.Status = statusDraft
+	}
+	if data.Status != statusDraft && data.Status != statusInReview {
+		logAndSendError(w, "Invalid status", http.StatusBadRequest, nil)
+		return
+	}
+
+	tags, err := normalizeTags(data.Tags)
+	if err != nil {
+		logAndSendError(w, "Invalid tags", http.StatusBadRequest, err)
+		return
+	}
+
+	// 禁止語を含むアイテムは追加できない
+	if !rejectBannedWords(w, data.Title, data.Content, tags) {
+		return
+	}
+
+	// 予約日時は承認時に予約公開にするために保存しておく
+	var publishAt interface{}
+	if data.PublishAt != nil {
+		if !data.PublishAt.After(time.Now()) {
+			logAndSendError(w, "publishAt must be in the future", http.StatusBadRequest, nil)
+			return
+		}
+		publishAt = data.PublishAt.UTC().Format("2006-01-02 15:04:05")
+	}
+
+	// 同じ内容・同じファイルのアイテムがすでにないかを確認
+	fingerprint, err := computeFingerprint(data.Title, data.Content, data.File)
+	if err != nil {
+		logAndSendError(w, "Failed to read file", http.StatusInternalServerError, err)
+		return
+	}
+	duplicates, err := findDuplicates(data.CourseID, data.CreatedBy, fingerprint)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+	if len(duplicates) > 0 && !data.Force && duplicatePolicy() == duplicatePolicyReject {
+		w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		responseData := map[string]interface{}{"message": "Similar items already exist", "duplicates": duplicates}
+		json.NewEncoder(w).Encode(responseData)
+		return
+	}
+
 	// ULIDを生成
 	id, err := generateULID()
 	if err != nil {
@@ -29,24 +111,54 @@ func HandleAddItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// アイテムとタグは同じトランザクションで保存する
+	tx, err := database.Db.Begin()
+	if err != nil {
+		logAndSendError(w, "Failed to begin transaction", http.StatusInternalServerError, err)
+		return
+	}
+	defer tx.Rollback()
+
 	// 挿入用のSQLクエリを作成
-	stmt, err := database.Db.Prepare("INSERT INTO items (id, title, content, category, chapter, file, fileType, createdBy, createdByName) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	stmt, err := tx.Prepare("INSERT INTO items (id, courseId, title, content, category, chapter, file, fileType, createdBy, createdByName, visibility, visibilityGroup, status, publishAt) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
 	if err != nil {
 		logAndSendError(w, "Failed to prepare SQL statement", http.StatusInternalServerError, err)
 		return
 	}
 
 	// データベースにデータを挿入
-	_, err = stmt.Exec(id, data.Title, data.Content, data.Category, data.Chapter, data.File, data.FileType, data.CreatedBy, data.CreatedByName)
+	_, err = stmt.Exec(id, data.CourseID, data.Title, data.Content, data.Category, data.Chapter, data.File, data.FileType, data.CreatedBy, data.CreatedByName, data.Visibility, visibilityGroupValue, data.Status, publishAt)
 	if err != nil {
 		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
 		return
 	}
 
+	if err := setItemTags(tx, data.CourseID, id, tags); err != nil {
+		logAndSendError(w, "Failed to save tags", http.StatusInternalServerError, err)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		logAndSendError(w, "Failed to commit transaction", http.StatusInternalServerError, err)
+		return
+	}
+
+	// 重複判定用のハッシュとコードブロックは検索用の情報のため、保存に失敗してもアイテムの追加は成功として返す
+	// （保存されなかった分は起動時の BackfillItemIndexes で作成する）
+	if err := saveFingerprint(id, fingerprint); err != nil {
+		log.Printf("Error: failed to save fingerprint for %s: %v\n", id, err)
+	}
+	if err := saveItemSnippets(id, data.Content); err != nil {
+		log.Printf("Error: failed to save code snippets for %s: %v\n", id, err)
+	}
+
+	// 画像ファイルの場合はサムネイルを生成
+	go generateThumbnails(id, data.File, data.FileType)
+	go refreshRelatedItems(id)
+
 	// 成功時のレスポンスを返す
 	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	responseData := map[string]string{"id": id}
+	responseData := map[string]interface{}{"id": id, "duplicates": duplicates}
 	json.NewEncoder(w).Encode(responseData)
 }
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+)
+
+// HandleSubmitForReview は作成者が下書き・差し戻されたアイテムのレビューを依頼する関数
+func HandleSubmitForReview(w http.ResponseWriter, r *http.Request) {
+	changeItemStatus(w, r, actionSubmit)
+}
+
+// HandleApproveItem はレビュアーがアイテムを承認する関数
+func HandleApproveItem(w http.ResponseWriter, r *http.Request) {
+	changeItemStatus(w, r, actionApprove)
+}
+
+// HandleRejectItem はレビュアーがコメント付きでアイテムを差し戻す関数
+func HandleRejectItem(w http.ResponseWriter, r *http.Request) {
+	changeItemStatus(w, r, actionReject)
+}
+
+// HandlePublishItem はレビュアーがアーカイブ済みのアイテムを再公開する関数
+func HandlePublishItem(w http.ResponseWriter, r *http.Request) {
+	changeItemStatus(w, r, actionPublish)
+}
+
+// HandleArchiveItem はアイテムをアーカイブして検索結果に表示されないようにする関数
+func HandleArchiveItem(w http.ResponseWriter, r *http.Request) {
+	changeItemStatus(w, r, actionArchive)
+}
+
+// changeItemStatus は状態遷移表（itemTransitions）に従ってアイテムの公開状態を変更する
+func changeItemStatus(w http.ResponseWriter, r *http.Request, action string) {
+	if r.Method != http.MethodPost {
+		logAndSendError(w, "Only POST requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		ItemID    string `json:"itemId"`
+		UserEmail string `json:"userEmail"`
+		Comment   string `json:"comment"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+
+	item, err := findItem(data.ItemID)
+	if err == sql.ErrNoRows {
+		logAndSendError(w, "Item not found", http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+
+	status, err := applyItemAction(item, action, data.UserEmail, data.Comment)
+	switch err {
+	case nil:
+	case errTransitionForbidden:
+		logAndSendError(w, "You are not allowed to "+action+" this item", http.StatusForbidden, err)
+		return
+	case errInvalidTransition:
+		logAndSendError(w, "Cannot "+action+" an item in status "+item.Status, http.StatusConflict, err)
+		return
+	case errCommentRequired:
+		logAndSendError(w, "Comment is required", http.StatusBadRequest, err)
+		return
+	default:
+		logAndSendError(w, "Failed to change status", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	responseData := map[string]string{"id": item.ID, "status": status}
+	json.NewEncoder(w).Encode(responseData)
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// HandleCollectGarbage は管理者からの依頼で未参照ファイルの削除（またはドライラン）を行う関数
+func HandleCollectGarbage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		logAndSendError(w, "Only POST requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		UserEmail   string `json:"userEmail"`
+		DryRun      *bool  `json:"dryRun"`      // 省略時はドライラン
+		GracePeriod string `json:"gracePeriod"` // 例: "48h"（省略時は24時間）
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+
+	if !isAdmin(data.UserEmail) {
+		logAndSendError(w, "Only administrators can run garbage collection", http.StatusForbidden, nil)
+		return
+	}
+
+	dryRun := data.DryRun == nil || *data.DryRun
+	grace := DefaultGCGracePeriod
+	if data.GracePeriod != "" {
+		d, err := time.ParseDuration(data.GracePeriod)
+		if err != nil || d < 0 {
+			logAndSendError(w, "Invalid grace period", http.StatusBadRequest, err)
+			return
+		}
+		grace = d
+	}
+
+	report, err := CollectGarbage(dryRun, grace)
+	if err != nil {
+		logAndSendError(w, "Failed to collect garbage", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(report)
+}
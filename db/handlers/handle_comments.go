@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"database/sql"
+	"db/database"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// HandleGetComments はアイテムのコメントを返信付きでページ単位に返す関数
+func HandleGetComments(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	query := r.URL.Query()
+	item, ok := findAccessibleItem(w, query.Get("courseId"), query.Get("itemId"), query.Get("userEmail"))
+	if !ok {
+		return
+	}
+
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(query.Get("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = defaultCommentPageSize
+	}
+	pageSize = min(pageSize, maxCommentPageSize)
+
+	comments, total, err := listComments(item.ID, page, pageSize)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	responseData := map[string]interface{}{"comments": comments, "total": total, "page": page, "pageSize": pageSize}
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// HandleAddComment はアイテムにコメント・返信を投稿する関数
+// 返信への返信はトップレベルのコメントへの返信として保存する
+func HandleAddComment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		logAndSendError(w, "Only POST requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		CourseID  string `json:"courseId"`
+		ItemID    string `json:"itemId"`
+		ParentID  string `json:"parentId"`
+		Body      string `json:"body"`
+		UserEmail string `json:"userEmail"`
+		UserName  string `json:"userName"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+	if data.UserEmail == "" || strings.TrimSpace(data.Body) == "" {
+		logAndSendError(w, "Required fields are missing", http.StatusBadRequest, nil)
+		return
+	}
+
+	item, ok := findAccessibleItem(w, data.CourseID, data.ItemID, data.UserEmail)
+	if !ok {
+		return
+	}
+
+	var parentID interface{}
+	if data.ParentID != "" {
+		parent, err := findComment(data.ParentID)
+		if err == sql.ErrNoRows || (err == nil && parent.ItemID != item.ID) {
+			logAndSendError(w, "Parent comment not found", http.StatusBadRequest, err)
+			return
+		}
+		if err != nil {
+			logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+			return
+		}
+		parentID = parent.ID
+		if parent.ParentID != "" {
+			parentID = parent.ParentID
+		}
+	}
+
+	id, err := generateULID()
+	if err != nil {
+		logAndSendError(w, "Failed to generate ULID", http.StatusInternalServerError, err)
+		return
+	}
+	_, err = database.Db.Exec("INSERT INTO comments (id, itemId, parentId, body, createdBy, createdByName) VALUES (?, ?, ?, ?, ?, ?)",
+		id, item.ID, parentID, data.Body, data.UserEmail, data.UserName)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	responseData := map[string]string{"id": id}
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// HandleUpdateComment は投稿者がコメントを編集する関数
+func HandleUpdateComment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		logAndSendError(w, "Only PUT requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		CommentID string `json:"commentId"`
+		Body      string `json:"body"`
+		UserEmail string `json:"userEmail"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+	if strings.TrimSpace(data.Body) == "" {
+		logAndSendError(w, "Required fields are missing", http.StatusBadRequest, nil)
+		return
+	}
+
+	comment, ok := findOwnComment(w, data.CommentID, data.UserEmail, false)
+	if !ok {
+		return
+	}
+	if comment.Deleted {
+		logAndSendError(w, "Deleted comments cannot be edited", http.StatusConflict, nil)
+		return
+	}
+
+	_, err := database.Db.Exec("UPDATE comments SET body = ?, updatedAt = NOW() WHERE id = ?", data.Body, comment.ID)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	responseData := map[string]string{"message": "更新が成功しました"}
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// HandleDeleteComment は投稿者またはコースの講師がコメントを削除する関数
+// 返信が付いている場合はスレッドを残すため本文だけを消す
+func HandleDeleteComment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		logAndSendError(w, "Only DELETE requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		CommentID string `json:"commentId"`
+		UserEmail string `json:"userEmail"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+
+	comment, ok := findOwnComment(w, data.CommentID, data.UserEmail, true)
+	if !ok {
+		return
+	}
+
+	if err := deleteComment(comment); err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app")
+	w.WriteHeader(http.StatusOK)
+	responseData := map[string]string{"message": "削除が成功しました"}
+	json.NewEncoder(w).Encode(responseData)
+}
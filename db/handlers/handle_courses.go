@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"db/database"
+	"encoding/json"
+	"net/http"
+)
+
+// Course はカテゴリ・章・アイテムを所有するコース（テナント）
+type Course struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	CreatedBy string `json:"createdBy"`
+	Role      string `json:"role"` // 呼び出したユーザーの役割
+}
+
+// CourseMember はコースのメンバー
+type CourseMember struct {
+	UserEmail string `json:"userEmail"`
+	Role      string `json:"role"`
+}
+
+// HandleGetCourses はユーザーが所属しているコースの一覧を返す関数
+func HandleGetCourses(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	rows, err := database.Db.Query(`
+		SELECT c.id, c.name, c.createdBy, m.role FROM courses c
+		JOIN course_members m ON m.courseId = c.id
+		WHERE m.userEmail = ? ORDER BY c.name`, r.URL.Query().Get("userEmail"))
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+	defer rows.Close()
+
+	courses := []Course{}
+	for rows.Next() {
+		var c Course
+		if err := rows.Scan(&c.ID, &c.Name, &c.CreatedBy, &c.Role); err != nil {
+			logAndSendError(w, "Failed to scan row", http.StatusInternalServerError, err)
+			return
+		}
+		courses = append(courses, c)
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(courses)
+}
+
+// HandleAddCourse はコースを作成し、作成者を owner として追加する関数
+func HandleAddCourse(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		logAndSendError(w, "Only POST requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		Name      string `json:"name"`
+		UserEmail string `json:"userEmail"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+	if data.Name == "" || data.UserEmail == "" {
+		logAndSendError(w, "Required fields are missing", http.StatusBadRequest, nil)
+		return
+	}
+
+	id, err := generateULID()
+	if err != nil {
+		logAndSendError(w, "Failed to generate ULID", http.StatusInternalServerError, err)
+		return
+	}
+
+	tx, err := database.Db.Begin()
+	if err != nil {
+		logAndSendError(w, "Failed to begin transaction", http.StatusInternalServerError, err)
+		return
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("INSERT INTO courses (id, name, createdBy) VALUES (?, ?, ?)", id, data.Name, data.UserEmail); err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+	if _, err := tx.Exec("INSERT INTO course_members (courseId, userEmail, role) VALUES (?, ?, ?)", id, data.UserEmail, courseRoleOwner); err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		logAndSendError(w, "Failed to commit transaction", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	responseData := map[string]string{"id": id}
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// HandleGetCourseMembers はコースのメンバー一覧をメンバーに返す関数
+func HandleGetCourseMembers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	query := r.URL.Query()
+	courseID := query.Get("courseId")
+	if !requireCourseMember(w, courseID, query.Get("userEmail")) {
+		return
+	}
+
+	rows, err := database.Db.Query("SELECT userEmail, role FROM course_members WHERE courseId = ? ORDER BY role, userEmail", courseID)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+	defer rows.Close()
+
+	members := []CourseMember{}
+	for rows.Next() {
+		var m CourseMember
+		if err := rows.Scan(&m.UserEmail, &m.Role); err != nil {
+			logAndSendError(w, "Failed to scan row", http.StatusInternalServerError, err)
+			return
+		}
+		members = append(members, m)
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(members)
+}
+
+// HandleChangeCourseMember はメンバーの追加・役割変更（POST）と削除（DELETE）を行う関数
+// 講師は受講者のみ、作成者は講師も管理できる
+func HandleChangeCourseMember(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+		logAndSendError(w, "Only POST and DELETE requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		CourseID    string `json:"courseId"`
+		UserEmail   string `json:"userEmail"`
+		MemberEmail string `json:"memberEmail"`
+		Role        string `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+	if data.MemberEmail == "" {
+		logAndSendError(w, "Required fields are missing", http.StatusBadRequest, nil)
+		return
+	}
+	if data.Role == "" {
+		data.Role = courseRoleStudent
+	}
+	if r.Method == http.MethodPost && (!isValidCourseRole(data.Role) || data.Role == courseRoleOwner) {
+		logAndSendError(w, "Invalid role", http.StatusBadRequest, nil)
+		return
+	}
+
+	actorRole, err := courseRole(data.CourseID, data.UserEmail)
+	if err != nil {
+		logAndSendError(w, "Failed to check course membership", http.StatusInternalServerError, err)
+		return
+	}
+	currentRole, err := courseRole(data.CourseID, data.MemberEmail)
+	if err != nil {
+		logAndSendError(w, "Failed to check course membership", http.StatusInternalServerError, err)
+		return
+	}
+
+	// 作成者は変更できず、講師の追加・変更・削除は作成者のみ
+	allowed := actorRole == courseRoleOwner || actorRole == courseRoleInstructor
+	if currentRole == courseRoleOwner {
+		allowed = false
+	}
+	if actorRole != courseRoleOwner && (currentRole == courseRoleInstructor || (r.Method == http.MethodPost && data.Role == courseRoleInstructor)) {
+		allowed = false
+	}
+	if !allowed {
+		logAndSendError(w, "You are not allowed to change this member", http.StatusForbidden, nil)
+		return
+	}
+
+	message := "メンバーを更新しました"
+	if r.Method == http.MethodDelete {
+		_, err = database.Db.Exec("DELETE FROM course_members WHERE courseId = ? AND userEmail = ?", data.CourseID, data.MemberEmail)
+		message = "メンバーを削除しました"
+	} else {
+		_, err = database.Db.Exec("INSERT INTO course_members (courseId, userEmail, role) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE role = VALUES(role)",
+			data.CourseID, data.MemberEmail, data.Role)
+	}
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	responseData := map[string]string{"message": message}
+	json.NewEncoder(w).Encode(responseData)
+}
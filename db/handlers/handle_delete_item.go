@@ -14,20 +14,47 @@ func HandleDeleteItem(w http.ResponseWriter, r *http.Request) {
 
 	// リクエストボディから削除対象のアイテムIDを取得
 	var data struct {
-		ItemIds []string `json:"itemIds"`
+		CourseID  string   `json:"courseId"`
+		UserEmail string   `json:"userEmail"`
+		ItemIds   []string `json:"itemIds"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
 		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
 		return
 	}
 
-	// アイテムを削除するSQLクエリを実行
+	// 削除できるのはコースのメンバーのみ
+	if !requireCourseMember(w, data.CourseID, data.UserEmail) {
+		return
+	}
+
+	// コースの講師はコース内のすべてのアイテムを、それ以外のメンバーは自分のアイテムのみ削除できる
+	staff, err := isCourseStaff(data.CourseID, data.UserEmail)
+	if err != nil {
+		logAndSendError(w, "Failed to check course membership", http.StatusInternalServerError, err)
+		return
+	}
+	query := "DELETE FROM items WHERE id = ? AND courseId = ?"
+	params := []interface{}{data.CourseID}
+	if !staff {
+		query += " AND createdBy = ?"
+		params = append(params, data.UserEmail)
+	}
+
+	// アイテムを削除するSQLクエリを実行（他のコースのアイテムは削除しない）
 	for _, itemId := range data.ItemIds {
-		_, err := database.Db.Exec("DELETE FROM items WHERE id = ?", itemId)
+		result, err := database.Db.Exec(query, append([]interface{}{itemId}, params...)...)
 		if err != nil {
 			logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
 			return
 		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			continue
+		}
+		if err := deleteItemRelations(itemId); err != nil {
+			logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+			return
+		}
 	}
 
 	// 削除が成功した場合のレスポンスを返す
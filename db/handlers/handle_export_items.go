@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// HandleExportItems は検索条件に一致する閲覧可能なアイテムをCSV（format が json の場合はJSON）で返す関数
+func HandleExportItems(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		logAndSendError(w, "Only POST requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var queryData struct {
+		searchFilter
+		Format string `json:"format"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&queryData); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+
+	// エクスポートできるのはコースのメンバーのみ
+	if !requireCourseMember(w, queryData.CourseID, queryData.UserEmail) {
+		return
+	}
+
+	// 検索と同じく閲覧できるアイテムのみに絞り込む
+	readableSQL, readableParams := readableItemsCondition(queryData.UserEmail)
+	sqlQuery, params := buildSearchQuery(queryData.searchFilter, []string{readableSQL}, readableParams)
+	items, err := queryItems(sqlQuery, params...)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	if queryData.Format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", `attachment; filename="items.json"`)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(items)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="items.csv"`)
+	w.WriteHeader(http.StatusOK)
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"id", "title", "content", "category", "chapter", "fileType", "createdByName", "createdAt", "updatedAt"})
+	for _, item := range items {
+		writer.Write([]string{
+			item.ID, item.Title, item.Content, item.Category, item.Chapter, item.FileType, item.CreatedByName,
+			item.CreatedAt.Format(time.RFC3339), item.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+	writer.Flush()
+}
@@ -0,0 +1,373 @@
+package handlers
+
+import (
+	"database/sql"
+	"db/database"
+	"db/model"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// 今日復習するカードとして一度に返す数の既定値と上限
+const (
+	defaultDueFlashcards = 20
+	maxDueFlashcards     = 100
+)
+
+// HandleGetFlashcardDecks はコース内のカードの束を、今日復習するカードの数付きで返す関数
+// itemId・category・chapter で絞り込める
+func HandleGetFlashcardDecks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	query := r.URL.Query()
+	courseID := query.Get("courseId")
+	userEmail := query.Get("userEmail")
+	if !requireCourseMember(w, courseID, userEmail) {
+		return
+	}
+
+	decks, err := listDecks(courseID, userEmail, query.Get("itemId"), query.Get("category"), query.Get("chapter"))
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(decks)
+}
+
+// HandleGetFlashcardDeck はカードの束を1件、カードと復習の状態付きで返す関数
+func HandleGetFlashcardDeck(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	query := r.URL.Query()
+	userEmail := query.Get("userEmail")
+	deck, ok := findMemberDeck(w, query.Get("courseId"), query.Get("id"), userEmail)
+	if !ok {
+		return
+	}
+
+	cards, err := deckCards(deck.ID, userEmail)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+	deck.Cards = cards
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(deck)
+}
+
+// HandleAddFlashcardDeck はアイテムまたは章に紐づくカードの束を作成する関数
+// generate が true の場合はアイテムの本文からカードを作成して追加する
+func HandleAddFlashcardDeck(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		logAndSendError(w, "Only POST requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	var data deckRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+	if data.Title == "" || (data.ItemID == "" && (data.Category == "" || data.Chapter == "")) {
+		logAndSendError(w, "Required fields are missing", http.StatusBadRequest, nil)
+		return
+	}
+	if !requireCourseMember(w, data.CourseID, data.UserEmail) {
+		return
+	}
+
+	// アイテムに紐づく場合はカテゴリ・章をアイテムに合わせる
+	var itemID interface{}
+	cards := data.Cards
+	if data.ItemID != "" {
+		item, ok := findAccessibleItem(w, data.CourseID, data.ItemID, data.UserEmail)
+		if !ok {
+			return
+		}
+		itemID = item.ID
+		data.Category, data.Chapter = item.Category, item.Chapter
+		if data.Generate {
+			cards = append(cards, flashcardsFromContent(item.Content)...)
+		}
+	} else if ok, err := courseHasCategory(data.CourseID, data.Category, data.Chapter); err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	} else if !ok {
+		logAndSendError(w, "Unknown category or chapter", http.StatusBadRequest, nil)
+		return
+	}
+
+	id, err := generateULID()
+	if err != nil {
+		logAndSendError(w, "Failed to generate ULID", http.StatusInternalServerError, err)
+		return
+	}
+
+	tx, err := database.Db.Begin()
+	if err != nil {
+		logAndSendError(w, "Failed to begin transaction", http.StatusInternalServerError, err)
+		return
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("INSERT INTO flashcard_decks (id, courseId, title, itemId, category, chapter, createdBy) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		id, data.CourseID, data.Title, itemID, data.Category, data.Chapter, data.UserEmail)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+	if err := saveFlashcards(tx, id, cards); err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		logAndSendError(w, "Failed to commit transaction", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	responseData := map[string]string{"id": id}
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// HandleUpdateFlashcardDeck は作成者・コースの講師がカードの束のタイトルとカードを更新する関数
+func HandleUpdateFlashcardDeck(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		logAndSendError(w, "Only PUT requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	var data deckRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+	if data.Title == "" {
+		logAndSendError(w, "Required fields are missing", http.StatusBadRequest, nil)
+		return
+	}
+	deck, ok := findEditableDeck(w, data.CourseID, data.ID, data.UserEmail)
+	if !ok {
+		return
+	}
+
+	tx, err := database.Db.Begin()
+	if err != nil {
+		logAndSendError(w, "Failed to begin transaction", http.StatusInternalServerError, err)
+		return
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("UPDATE flashcard_decks SET title = ?, updatedAt = NOW() WHERE id = ?", data.Title, deck.ID); err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+	if err := saveFlashcards(tx, deck.ID, data.Cards); err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		logAndSendError(w, "Failed to commit transaction", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	responseData := map[string]string{"message": "更新が成功しました"}
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// HandleDeleteFlashcardDeck は作成者・コースの講師がカードの束を削除する関数
+func HandleDeleteFlashcardDeck(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		logAndSendError(w, "Only DELETE requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	var data deckRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+	deck, ok := findEditableDeck(w, data.CourseID, data.ID, data.UserEmail)
+	if !ok {
+		return
+	}
+
+	tx, err := database.Db.Begin()
+	if err != nil {
+		logAndSendError(w, "Failed to begin transaction", http.StatusInternalServerError, err)
+		return
+	}
+	defer tx.Rollback()
+
+	if err := deleteDeck(tx, deck.ID); err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		logAndSendError(w, "Failed to commit transaction", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app")
+	w.WriteHeader(http.StatusOK)
+	responseData := map[string]string{"message": "削除が成功しました"}
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// HandleGetDueFlashcards は呼び出したユーザーが今日復習するカードを返す関数
+// deckId を指定した場合はその束のカードだけを返す
+func HandleGetDueFlashcards(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	query := r.URL.Query()
+	courseID := query.Get("courseId")
+	userEmail := query.Get("userEmail")
+	if !requireCourseMember(w, courseID, userEmail) {
+		return
+	}
+
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit < 1 {
+		limit = defaultDueFlashcards
+	}
+	limit = min(limit, maxDueFlashcards)
+
+	cards, err := dueFlashcards(courseID, userEmail, query.Get("deckId"), limit)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(cards)
+}
+
+// HandleReviewFlashcard はカードを思い出せた度合い（0〜5）を記録し、次の復習日を返す関数
+func HandleReviewFlashcard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		logAndSendError(w, "Only POST requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		CourseID  string `json:"courseId"`
+		CardID    string `json:"cardId"`
+		UserEmail string `json:"userEmail"`
+		Quality   *int   `json:"quality"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+	if data.Quality == nil || *data.Quality < 0 || *data.Quality > 5 {
+		logAndSendError(w, "Quality must be between 0 and 5", http.StatusBadRequest, nil)
+		return
+	}
+	if !requireCourseMember(w, data.CourseID, data.UserEmail) {
+		return
+	}
+
+	// 閲覧できる束のカードのみ復習できる
+	var deckID string
+	err := database.Db.QueryRow("SELECT deckId FROM flashcards WHERE id = ?", data.CardID).Scan(&deckID)
+	if err == nil {
+		_, err = findDeck(data.CourseID, deckID, data.UserEmail)
+	}
+	if err == sql.ErrNoRows {
+		logAndSendError(w, "Flashcard not found", http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+
+	state, err := recordFlashcardReview(data.CardID, data.UserEmail, *data.Quality)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	responseData := map[string]interface{}{
+		"cardId":       data.CardID,
+		"dueDate":      state.DueDate.Format("2006-01-02"),
+		"intervalDays": state.IntervalDays,
+		"repetitions":  state.Repetitions,
+	}
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// deckRequest はカードの束の作成・更新・削除のリクエストボディ
+type deckRequest struct {
+	ID        string            `json:"id"`
+	CourseID  string            `json:"courseId"`
+	UserEmail string            `json:"userEmail"`
+	Title     string            `json:"title"`
+	ItemID    string            `json:"itemId"`
+	Category  string            `json:"category"`
+	Chapter   string            `json:"chapter"`
+	Cards     []model.Flashcard `json:"cards"`
+	Generate  bool              `json:"generate"`
+}
+
+// findMemberDeck はコースのメンバーがカードの束を取得する
+// 取得できない場合はエラーレスポンスを返して false を返す
+func findMemberDeck(w http.ResponseWriter, courseID, deckID, userEmail string) (model.FlashcardDeck, bool) {
+	if !requireCourseMember(w, courseID, userEmail) {
+		return model.FlashcardDeck{}, false
+	}
+	deck, err := findDeck(courseID, deckID, userEmail)
+	if err == sql.ErrNoRows {
+		logAndSendError(w, "Flashcard deck not found", http.StatusNotFound, err)
+		return deck, false
+	}
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return deck, false
+	}
+	return deck, true
+}
+
+// findEditableDeck は作成者・コースの講師が編集するカードの束を取得する
+func findEditableDeck(w http.ResponseWriter, courseID, deckID, userEmail string) (model.FlashcardDeck, bool) {
+	deck, ok := findMemberDeck(w, courseID, deckID, userEmail)
+	if !ok {
+		return deck, false
+	}
+	if editable, err := canEditDeck(deck, userEmail); err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return deck, false
+	} else if !editable {
+		logAndSendError(w, "Only the author or course staff can change this deck", http.StatusForbidden, nil)
+		return deck, false
+	}
+	return deck, true
+}
@@ -6,8 +6,15 @@ import (
 	"net/http"
 )
 
-func HandleGetCategoryNames(w http.ResponseWriter) {
-	rows, err := database.Db.Query("SELECT Name FROM categories")
+func HandleGetCategoryNames(w http.ResponseWriter, r *http.Request) {
+	// コースのメンバーのみ取得できる
+	query := r.URL.Query()
+	courseID := query.Get("courseId")
+	if !requireCourseMember(w, courseID, query.Get("userEmail")) {
+		return
+	}
+
+	rows, err := database.Db.Query("SELECT Name FROM categories WHERE courseId = ?", courseID)
 	if err != nil {
 		http.Error(w, "データベースのクエリエラー", http.StatusInternalServerError)
 		return
@@ -33,3 +40,45 @@ func HandleGetCategoryNames(w http.ResponseWriter) {
 	w.Header().Set("Content-Type", "application/json")
 	w.Write(response)
 }
+
+// HandleAddCategoryName はコースの講師がカテゴリを追加する関数
+func HandleAddCategoryName(w http.ResponseWriter, r *http.Request) {
+	addCourseName(w, r, "INSERT IGNORE INTO categories (courseId, Name) VALUES (?, ?)")
+}
+
+// addCourseName はコースにカテゴリ・章の名前を追加する
+func addCourseName(w http.ResponseWriter, r *http.Request, query string) {
+	if r.Method != http.MethodPost {
+		logAndSendError(w, "Only POST requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		CourseID  string `json:"courseId"`
+		UserEmail string `json:"userEmail"`
+		Name      string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+	if data.Name == "" {
+		logAndSendError(w, "Required fields are missing", http.StatusBadRequest, nil)
+		return
+	}
+	if !requireCourseStaff(w, data.CourseID, data.UserEmail) {
+		return
+	}
+
+	if _, err := database.Db.Exec(query, data.CourseID, data.Name); err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	responseData := map[string]string{"name": data.Name}
+	json.NewEncoder(w).Encode(responseData)
+}
@@ -6,8 +6,15 @@ import (
 	"net/http"
 )
 
-func HandleGetChapterNames(w http.ResponseWriter) {
-	rows, err := database.Db.Query("SELECT Name FROM chapters")
+func HandleGetChapterNames(w http.ResponseWriter, r *http.Request) {
+	// コースのメンバーのみ取得できる
+	query := r.URL.Query()
+	courseID := query.Get("courseId")
+	if !requireCourseMember(w, courseID, query.Get("userEmail")) {
+		return
+	}
+
+	rows, err := database.Db.Query("SELECT Name FROM chapters WHERE courseId = ?", courseID)
 	if err != nil {
 		http.Error(w, "データベースのクエリエラー", http.StatusInternalServerError)
 		return
@@ -33,3 +40,8 @@ func HandleGetChapterNames(w http.ResponseWriter) {
 	w.Header().Set("Content-Type", "application/json")
 	w.Write(response)
 }
+
+// HandleAddChapterName はコースの講師が章を追加する関数
+func HandleAddChapterName(w http.ResponseWriter, r *http.Request) {
+	addCourseName(w, r, "INSERT IGNORE INTO chapters (courseId, Name) VALUES (?, ?)")
+}
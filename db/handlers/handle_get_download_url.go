@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// HandleGetDownloadURL はアイテムの添付ファイルの署名付きURLを発行する関数
+func HandleGetDownloadURL(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		logAndSendError(w, "Only POST requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		CourseID  string `json:"courseId"`
+		ItemID    string `json:"itemId"`
+		UserEmail string `json:"userEmail"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+
+	item, err := findCourseItem(data.CourseID, data.ItemID)
+	if err == sql.ErrNoRows {
+		logAndSendError(w, "Item not found", http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+
+	// 公開範囲に応じて発行できるかを判定
+	allowed, err := canAccessItem(item, data.UserEmail)
+	if err != nil {
+		logAndSendError(w, "Failed to check access", http.StatusInternalServerError, err)
+		return
+	}
+	if !allowed {
+		logAndSendError(w, "You are not allowed to download this file", http.StatusForbidden, nil)
+		return
+	}
+	if item.File == "" {
+		logAndSendError(w, "Item has no file", http.StatusNotFound, nil)
+		return
+	}
+
+	// 外部URLが登録されている場合はそのまま返す
+	responseData := map[string]string{"url": item.File}
+	if isStoredFile(item.File) {
+		u, expiresAt, err := signDownloadURL(item.File, downloadURLTTL)
+		if err != nil {
+			logAndSendError(w, "Failed to sign download URL", http.StatusInternalServerError, err)
+			return
+		}
+		responseData["url"] = u
+		responseData["expiresAt"] = expiresAt.Format(time.RFC3339)
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(responseData)
+}
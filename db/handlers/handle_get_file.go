@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"db/storage"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// ストレージ上のファイルを配信するエンドポイントのパス
+const filesPath = "/api/files/"
+
+// inlineFileTypes はブラウザでそのまま表示するファイルの種類（それ以外はダウンロードさせる）
+var inlineFileTypes = map[string]bool{
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+}
+
+// HandleGetFile は署名付きURLを検証し、ストレージに保存されたファイルを返す関数
+func HandleGetFile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, filesPath)
+	query := r.URL.Query()
+	if !verifyDownloadURL(key, query.Get("expires"), query.Get("sig")) {
+		logAndSendError(w, "Invalid or expired download URL", http.StatusForbidden, nil)
+		return
+	}
+
+	f, err := storage.Store.Open(key)
+	if err != nil {
+		logAndSendError(w, "File not found", http.StatusNotFound, err)
+		return
+	}
+	defer f.Close()
+
+	// API と同じオリジンでスクリプトが実行されないよう、ブラウザでそのまま表示するのは inlineFileTypes のみにする
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	disposition := "inline"
+	if !inlineFileTypes[mediaType] {
+		disposition = mime.FormatMediaType("attachment", map[string]string{"filename": path.Base(key)})
+		if disposition == "" {
+			disposition = "attachment"
+		}
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", disposition)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, f)
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"database/sql"
+	"db/model"
+	"encoding/json"
+	"net/http"
+)
+
+// HandleGetItem はIDを指定してアイテムを1件返す関数
+// 閲覧できないアイテムは存在を知られないよう 404 を返す
+func HandleGetItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	query := r.URL.Query()
+	item, err := findCourseItem(query.Get("courseId"), query.Get("id"))
+	if err == sql.ErrNoRows {
+		logAndSendError(w, "Item not found", http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+
+	allowed, err := canAccessItem(item, query.Get("userEmail"))
+	if err != nil {
+		logAndSendError(w, "Failed to check access", http.StatusInternalServerError, err)
+		return
+	}
+	if !allowed {
+		logAndSendError(w, "Item not found", http.StatusNotFound, nil)
+		return
+	}
+
+	items := []model.Item{item}
+	if err := decorateItems(items, query.Get("userEmail")); err != nil {
+		logAndSendError(w, "Failed to load item details", http.StatusInternalServerError, err)
+		return
+	}
+
+	// render=html の場合は本文を HTML に変換して目次と合わせて返す
+	if query.Get("render") == "html" {
+		renderContent(&items[0])
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(items[0])
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"db/database"
+	"encoding/json"
+	"net/http"
+)
+
+// Group は公開範囲の指定に使うユーザーのグループ
+type Group struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	CreatedBy string `json:"createdBy"`
+}
+
+// HandleGetGroups はユーザーが所属しているグループの一覧を返す関数
+func HandleGetGroups(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	userEmail := r.URL.Query().Get("userEmail")
+	rows, err := database.Db.Query(`
+		SELECT g.id, g.name, g.createdBy FROM user_groups g
+		JOIN user_group_members m ON m.groupId = g.id
+		WHERE m.userEmail = ? ORDER BY g.name`, userEmail)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+	defer rows.Close()
+
+	groups := []Group{}
+	for rows.Next() {
+		var g Group
+		if err := rows.Scan(&g.ID, &g.Name, &g.CreatedBy); err != nil {
+			logAndSendError(w, "Failed to scan row", http.StatusInternalServerError, err)
+			return
+		}
+		groups = append(groups, g)
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(groups)
+}
+
+// HandleAddGroup はグループを作成し、作成者をメンバーに追加する関数
+func HandleAddGroup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		logAndSendError(w, "Only POST requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		Name      string `json:"name"`
+		UserEmail string `json:"userEmail"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+	if data.Name == "" || data.UserEmail == "" {
+		logAndSendError(w, "Required fields are missing", http.StatusBadRequest, nil)
+		return
+	}
+
+	id, err := generateULID()
+	if err != nil {
+		logAndSendError(w, "Failed to generate ULID", http.StatusInternalServerError, err)
+		return
+	}
+
+	tx, err := database.Db.Begin()
+	if err != nil {
+		logAndSendError(w, "Failed to begin transaction", http.StatusInternalServerError, err)
+		return
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("INSERT INTO user_groups (id, name, createdBy) VALUES (?, ?, ?)", id, data.Name, data.UserEmail); err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+	if _, err := tx.Exec("INSERT INTO user_group_members (groupId, userEmail) VALUES (?, ?)", id, data.UserEmail); err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		logAndSendError(w, "Failed to commit transaction", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	responseData := map[string]string{"id": id}
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// HandleChangeGroupMember はグループの作成者がメンバーを追加（POST）・削除（DELETE）する関数
+func HandleChangeGroupMember(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+		logAndSendError(w, "Only POST and DELETE requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		GroupID     string `json:"groupId"`
+		UserEmail   string `json:"userEmail"`
+		MemberEmail string `json:"memberEmail"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+	if data.MemberEmail == "" {
+		logAndSendError(w, "Required fields are missing", http.StatusBadRequest, nil)
+		return
+	}
+
+	owner, err := groupOwner(data.GroupID)
+	if err == errGroupNotFound {
+		logAndSendError(w, "Group not found", http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+	if data.UserEmail == "" || owner != data.UserEmail {
+		logAndSendError(w, "Only the group owner can change members", http.StatusForbidden, nil)
+		return
+	}
+
+	query := "INSERT IGNORE INTO user_group_members (groupId, userEmail) VALUES (?, ?)"
+	message := "メンバーを追加しました"
+	if r.Method == http.MethodDelete {
+		if data.MemberEmail == owner {
+			logAndSendError(w, "The group owner cannot be removed", http.StatusBadRequest, nil)
+			return
+		}
+		query = "DELETE FROM user_group_members WHERE groupId = ? AND userEmail = ?"
+		message = "メンバーを削除しました"
+	}
+	if _, err := database.Db.Exec(query, data.GroupID, data.MemberEmail); err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	responseData := map[string]string{"message": message}
+	json.NewEncoder(w).Encode(responseData)
+}
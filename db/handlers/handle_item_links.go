@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"db/database"
+	"db/model"
+	"encoding/json"
+	"net/http"
+)
+
+// HandleGetItemLinks はアイテムの関係（関係元・関係先の両方向）を返す関数
+func HandleGetItemLinks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	query := r.URL.Query()
+	userEmail := query.Get("userEmail")
+	item, ok := findAccessibleItem(w, query.Get("courseId"), query.Get("itemId"), userEmail)
+	if !ok {
+		return
+	}
+
+	outgoing, incoming, err := listItemLinks(item.ID, userEmail)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	responseData := map[string]interface{}{"itemId": item.ID, "outgoing": outgoing, "incoming": incoming}
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// HandleAddItemLink はアイテム間の関係を追加する関数
+// 関係の元のアイテムの作成者かコースの講師が、どちらのアイテムも閲覧できる場合のみ追加できる
+func HandleAddItemLink(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		logAndSendError(w, "Only POST requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	data, ok := decodeItemLinkRequest(w, r)
+	if !ok {
+		return
+	}
+	if !isValidLinkType(data.Type) {
+		logAndSendError(w, "Invalid link type", http.StatusBadRequest, nil)
+		return
+	}
+	if !requireCourseMember(w, data.CourseID, data.UserEmail) {
+		return
+	}
+	item, ok := findAccessibleItem(w, data.CourseID, data.ItemID, data.UserEmail)
+	if !ok {
+		return
+	}
+	if !requireItemAuthorOrStaff(w, item, data.UserEmail) {
+		return
+	}
+	if _, ok := findAccessibleItem(w, data.CourseID, data.TargetID, data.UserEmail); !ok {
+		return
+	}
+
+	err := addItemLink(data.CourseID, model.ItemLink{ItemID: data.ItemID, TargetID: data.TargetID, Type: data.Type, CreatedBy: data.UserEmail})
+	if err == errSelfLink || err == errPrerequisiteCycle {
+		logAndSendError(w, err.Error(), http.StatusConflict, err)
+		return
+	}
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	responseData := map[string]string{"message": "追加が成功しました"}
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// HandleDeleteItemLink はアイテム間の関係を削除する関数
+// 関係の元のアイテムの作成者かコースの講師のみ削除できる
+func HandleDeleteItemLink(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		logAndSendError(w, "Only DELETE requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	data, ok := decodeItemLinkRequest(w, r)
+	if !ok {
+		return
+	}
+	if !requireCourseMember(w, data.CourseID, data.UserEmail) {
+		return
+	}
+	item, ok := findAccessibleItem(w, data.CourseID, data.ItemID, data.UserEmail)
+	if !ok {
+		return
+	}
+	if !requireItemAuthorOrStaff(w, item, data.UserEmail) {
+		return
+	}
+
+	_, err := database.Db.Exec("DELETE FROM item_links WHERE itemId = ? AND targetId = ? AND linkType = ?", data.ItemID, data.TargetID, data.Type)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app")
+	w.WriteHeader(http.StatusOK)
+	responseData := map[string]string{"message": "削除が成功しました"}
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// HandleGetChapterGraph は章のアイテムの依存関係グラフを返す関数
+func HandleGetChapterGraph(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	query := r.URL.Query()
+	courseID := query.Get("courseId")
+	userEmail := query.Get("userEmail")
+	if !requireCourseMember(w, courseID, userEmail) {
+		return
+	}
+
+	graph, err := chapterGraph(courseID, query.Get("category"), query.Get("chapter"), userEmail)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+	if err := decorateItems(graph.Nodes, userEmail); err != nil {
+		logAndSendError(w, "Failed to load item details", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(graph)
+}
+
+// itemLinkRequest はアイテム間の関係の追加・削除のリクエストボディ
+type itemLinkRequest struct {
+	CourseID  string `json:"courseId"`
+	UserEmail string `json:"userEmail"`
+	ItemID    string `json:"itemId"`
+	TargetID  string `json:"targetId"`
+	Type      string `json:"type"`
+}
+
+func decodeItemLinkRequest(w http.ResponseWriter, r *http.Request) (itemLinkRequest, bool) {
+	var data itemLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return data, false
+	}
+	if data.ItemID == "" || data.TargetID == "" || data.Type == "" {
+		logAndSendError(w, "Required fields are missing", http.StatusBadRequest, nil)
+		return data, false
+	}
+	return data, true
+}
@@ -0,0 +1,326 @@
+package handlers
+
+import (
+	"database/sql"
+	"db/database"
+	"db/model"
+	"encoding/json"
+	"net/http"
+)
+
+// HandleGetLearningPaths はコースの学習パスの一覧を、ユーザーの進捗付きで返す関数
+func HandleGetLearningPaths(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	query := r.URL.Query()
+	courseID := query.Get("courseId")
+	userEmail := query.Get("userEmail")
+	if !requireCourseMember(w, courseID, userEmail) {
+		return
+	}
+
+	paths, err := queryLearningPaths(userEmail, "SELECT id, courseId, title, description, createdBy, createdAt, updatedAt FROM learning_paths WHERE courseId = ? ORDER BY title", courseID)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+	if err := attachPathProgress(paths, userEmail); err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(paths)
+}
+
+// HandleGetLearningPath は学習パスを1件、閲覧できるアイテムを順番に並べて返す関数
+func HandleGetLearningPath(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	query := r.URL.Query()
+	courseID := query.Get("courseId")
+	userEmail := query.Get("userEmail")
+	path, ok := findMemberLearningPath(w, courseID, query.Get("id"), userEmail)
+	if !ok {
+		return
+	}
+
+	items, err := pathItems(path, userEmail)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+	if err := decorateItems(items, userEmail); err != nil {
+		logAndSendError(w, "Failed to load item details", http.StatusInternalServerError, err)
+		return
+	}
+	path.Items = items
+	paths := []model.LearningPath{path}
+	if err := attachPathProgress(paths, userEmail); err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(paths[0])
+}
+
+// HandleLearningPathNavigation は学習パス内でのアイテムの前後のアイテムを返す関数
+// 閲覧できないアイテムは飛ばす
+func HandleLearningPathNavigation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	query := r.URL.Query()
+	userEmail := query.Get("userEmail")
+	itemID := query.Get("itemId")
+	path, ok := findMemberLearningPath(w, query.Get("courseId"), query.Get("pathId"), userEmail)
+	if !ok {
+		return
+	}
+
+	items, err := pathItems(path, userEmail)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+	position := -1
+	for i, item := range items {
+		if item.ID == itemID {
+			position = i
+		}
+	}
+	if position < 0 {
+		logAndSendError(w, "Item is not in this learning path", http.StatusNotFound, nil)
+		return
+	}
+
+	var previous, next *model.Item
+	if position > 0 {
+		previous = &items[position-1]
+	}
+	if position < len(items)-1 {
+		next = &items[position+1]
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	responseData := map[string]interface{}{
+		"pathId":   path.ID,
+		"position": position + 1,
+		"total":    len(items),
+		"previous": previous,
+		"next":     next,
+	}
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// HandleAddLearningPath はコースの講師が学習パスを作成する関数
+func HandleAddLearningPath(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		logAndSendError(w, "Only POST requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	var data learningPathRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+	if data.Title == "" {
+		logAndSendError(w, "Required fields are missing", http.StatusBadRequest, nil)
+		return
+	}
+	if !requireCourseStaff(w, data.CourseID, data.UserEmail) {
+		return
+	}
+
+	id, err := generateULID()
+	if err != nil {
+		logAndSendError(w, "Failed to generate ULID", http.StatusInternalServerError, err)
+		return
+	}
+
+	tx, err := database.Db.Begin()
+	if err != nil {
+		logAndSendError(w, "Failed to begin transaction", http.StatusInternalServerError, err)
+		return
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("INSERT INTO learning_paths (id, courseId, title, description, createdBy) VALUES (?, ?, ?, ?, ?)",
+		id, data.CourseID, data.Title, data.Description, data.UserEmail)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+	if !savePathItems(w, tx, data.CourseID, id, data.ItemIDs) {
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		logAndSendError(w, "Failed to commit transaction", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	responseData := map[string]string{"id": id}
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// HandleUpdateLearningPath はコースの講師が学習パスのタイトル・説明・アイテムの順番を更新する関数
+func HandleUpdateLearningPath(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		logAndSendError(w, "Only PUT requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	var data learningPathRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+	if data.Title == "" {
+		logAndSendError(w, "Required fields are missing", http.StatusBadRequest, nil)
+		return
+	}
+	if !requireCourseStaff(w, data.CourseID, data.UserEmail) {
+		return
+	}
+
+	tx, err := database.Db.Begin()
+	if err != nil {
+		logAndSendError(w, "Failed to begin transaction", http.StatusInternalServerError, err)
+		return
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE learning_paths SET title = ?, description = ?, updatedAt = NOW() WHERE id = ? AND courseId = ?",
+		data.Title, data.Description, data.ID, data.CourseID)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		logAndSendError(w, "Learning path not found", http.StatusNotFound, nil)
+		return
+	}
+	if !savePathItems(w, tx, data.CourseID, data.ID, data.ItemIDs) {
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		logAndSendError(w, "Failed to commit transaction", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	responseData := map[string]string{"message": "更新が成功しました"}
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// HandleDeleteLearningPath はコースの講師が学習パスを削除する関数
+func HandleDeleteLearningPath(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		logAndSendError(w, "Only DELETE requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	var data learningPathRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+	if !requireCourseStaff(w, data.CourseID, data.UserEmail) {
+		return
+	}
+
+	result, err := database.Db.Exec("DELETE FROM learning_paths WHERE id = ? AND courseId = ?", data.ID, data.CourseID)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+	if n, _ := result.RowsAffected(); n > 0 {
+		if _, err := database.Db.Exec("DELETE FROM learning_path_items WHERE pathId = ?", data.ID); err != nil {
+			logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app")
+	w.WriteHeader(http.StatusOK)
+	responseData := map[string]string{"message": "削除が成功しました"}
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// learningPathRequest は学習パスの作成・更新・削除のリクエストボディ
+type learningPathRequest struct {
+	ID          string   `json:"id"`
+	CourseID    string   `json:"courseId"`
+	UserEmail   string   `json:"userEmail"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	ItemIDs     []string `json:"itemIds"`
+}
+
+// findMemberLearningPath はコースのメンバーが学習パスを取得する
+// 取得できない場合はエラーレスポンスを返して false を返す
+func findMemberLearningPath(w http.ResponseWriter, courseID, pathID, userEmail string) (model.LearningPath, bool) {
+	if !requireCourseMember(w, courseID, userEmail) {
+		return model.LearningPath{}, false
+	}
+	path, err := findLearningPath(courseID, pathID, userEmail)
+	if err == sql.ErrNoRows {
+		logAndSendError(w, "Learning path not found", http.StatusNotFound, err)
+		return path, false
+	}
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return path, false
+	}
+	return path, true
+}
+
+// pathItems は学習パスのアイテムのうち、ユーザーが閲覧できるものを順番どおりに返す
+func pathItems(path model.LearningPath, userEmail string) ([]model.Item, error) {
+	items := []model.Item{}
+	if len(path.ItemIDs) == 0 {
+		return items, nil
+	}
+	readableSQL, readableParams := readableItemsCondition(userEmail)
+	found, err := queryItems("SELECT "+itemColumns+" FROM items JOIN learning_path_items lp ON lp.itemId = items.id"+
+		" WHERE lp.pathId = ? AND "+readableSQL+" ORDER BY lp.position", append([]interface{}{path.ID}, readableParams...)...)
+	if err != nil {
+		return nil, err
+	}
+	return append(items, found...), nil
+}
+
+// savePathItems は学習パスのアイテムを保存し、失敗した場合はエラーレスポンスを返して false を返す
+func savePathItems(w http.ResponseWriter, tx *sql.Tx, courseID, pathID string, itemIDs []string) bool {
+	err := saveLearningPathItems(tx, courseID, pathID, itemIDs)
+	if err == errPathItemNotInCourse {
+		logAndSendError(w, "Items must belong to the course", http.StatusBadRequest, err)
+		return false
+	}
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return false
+	}
+	return true
+}
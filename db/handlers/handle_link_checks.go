@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// HandleGetBrokenLinks はコース内のリンク切れを作成者ごとに返す関数
+// コースの講師はすべての作成者の、それ以外のメンバーは自分のアイテムのリンク切れのみ取得できる
+func HandleGetBrokenLinks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	query := r.URL.Query()
+	courseID := query.Get("courseId")
+	userEmail := query.Get("userEmail")
+	if !requireCourseMember(w, courseID, userEmail) {
+		return
+	}
+
+	author := query.Get("author")
+	staff, err := isCourseStaff(courseID, userEmail)
+	if err != nil {
+		logAndSendError(w, "Failed to check course membership", http.StatusInternalServerError, err)
+		return
+	}
+	if !staff {
+		author = userEmail
+	}
+
+	report, err := brokenLinksByAuthor(courseID, author)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(report)
+}
+
+// HandleCheckLinks は管理者からの依頼でリンクの確認を開始する関数
+// 確認には時間がかかるため、バックグラウンドで実行してすぐに 202 を返す
+func HandleCheckLinks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		logAndSendError(w, "Only POST requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		UserEmail string `json:"userEmail"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+	if !isAdmin(data.UserEmail) {
+		logAndSendError(w, "Only administrators can check links", http.StatusForbidden, nil)
+		return
+	}
+
+	go func() {
+		report, err := DefaultLinkChecker.Run(context.Background())
+		if err != nil {
+			log.Printf("Error: link check failed: %v\n", err)
+			return
+		}
+		log.Printf("Link check: %d broken of %d links in %d items\n", report.Broken, report.Links, report.Items)
+	}()
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	responseData := map[string]string{"message": "リンクの確認を開始しました"}
+	json.NewEncoder(w).Encode(responseData)
+}
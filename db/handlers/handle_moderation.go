@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// HandleReportItem は不適切・誤りのあるアイテムを通報する関数
+// 閲覧できるアイテムのみ通報でき、同じアイテムへの未対応の通報は1人1件まで
+func HandleReportItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		logAndSendError(w, "Only POST requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		CourseID  string `json:"courseId"`
+		ItemID    string `json:"itemId"`
+		UserEmail string `json:"userEmail"`
+		Reason    string `json:"reason"`
+		Comment   string `json:"comment"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+	if data.UserEmail == "" {
+		logAndSendError(w, "userEmail is required", http.StatusBadRequest, nil)
+		return
+	}
+	if !isValidReportReason(data.Reason) {
+		logAndSendError(w, "Invalid reason", http.StatusBadRequest, nil)
+		return
+	}
+	data.Comment = strings.TrimSpace(data.Comment)
+	if data.Reason == reportReasonOther && data.Comment == "" {
+		logAndSendError(w, "comment is required when reason is other", http.StatusBadRequest, nil)
+		return
+	}
+	if utf8.RuneCountInString(data.Comment) > 1000 {
+		logAndSendError(w, "comment is too long", http.StatusBadRequest, nil)
+		return
+	}
+
+	item, ok := findAccessibleItem(w, data.CourseID, data.ItemID, data.UserEmail)
+	if !ok {
+		return
+	}
+	if item.CreatedBy == data.UserEmail {
+		logAndSendError(w, "You cannot report your own item", http.StatusForbidden, nil)
+		return
+	}
+
+	report, created, err := createReport(item.ID, data.UserEmail, data.Reason, data.Comment)
+	if err != nil {
+		logAndSendError(w, "Failed to save report", http.StatusInternalServerError, err)
+		return
+	}
+	if !created {
+		logAndSendError(w, "You have already reported this item", http.StatusConflict, nil)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(report)
+}
+
+// HandleModerationQueue は管理者向けに未対応の通報があるアイテムの一覧を返す関数
+// courseId を指定した場合はそのコースのアイテムのみを返す
+func HandleModerationQueue(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	query := r.URL.Query()
+	if !isAdmin(query.Get("userEmail")) {
+		logAndSendError(w, "Only administrators can view the moderation queue", http.StatusForbidden, nil)
+		return
+	}
+
+	queue, err := moderationQueue(query.Get("courseId"))
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(queue)
+}
+
+// HandleModerateItem は管理者がアイテムを非表示（hide）・再表示（restore）にする、または通報を却下（dismiss）する関数
+// 対応は作成者に通知し、監査ログに記録する
+func HandleModerateItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		logAndSendError(w, "Only POST requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		UserEmail string `json:"userEmail"`
+		ItemID    string `json:"itemId"`
+		Action    string `json:"action"`
+		Note      string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+	if !isAdmin(data.UserEmail) {
+		logAndSendError(w, "Only administrators can moderate items", http.StatusForbidden, nil)
+		return
+	}
+	data.Note = strings.TrimSpace(data.Note)
+	if utf8.RuneCountInString(data.Note) > 1000 {
+		logAndSendError(w, "note is too long", http.StatusBadRequest, nil)
+		return
+	}
+
+	item, err := findItem(data.ItemID)
+	if err == sql.ErrNoRows {
+		logAndSendError(w, "Item not found", http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+
+	switch data.Action {
+	case moderationActionHide:
+		if item.Hidden {
+			logAndSendError(w, "Item is already hidden", http.StatusConflict, nil)
+			return
+		}
+	case moderationActionRestore:
+		if !item.Hidden {
+			logAndSendError(w, "Item is not hidden", http.StatusConflict, nil)
+			return
+		}
+	case moderationActionDismiss:
+	default:
+		logAndSendError(w, "Invalid action", http.StatusBadRequest, nil)
+		return
+	}
+
+	handled, err := moderateItem(item, data.UserEmail, data.Action, data.Note)
+	if err != nil {
+		logAndSendError(w, "Failed to moderate item", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	responseData := map[string]interface{}{"itemId": item.ID, "action": data.Action, "reports": handled}
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// HandleModerationLog は管理者向けに対応の履歴を新しい順に返す関数
+// itemId を指定した場合はそのアイテムの履歴のみを返す
+func HandleModerationLog(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	query := r.URL.Query()
+	if !isAdmin(query.Get("userEmail")) {
+		logAndSendError(w, "Only administrators can view the moderation log", http.StatusForbidden, nil)
+		return
+	}
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit < 1 || limit > 500 {
+		limit = 100
+	}
+
+	entries, err := moderationLog(query.Get("itemId"), limit)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(entries)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"db/database"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// HandleGetNotifications はユーザーへの通知を新しい順に返す関数
+func HandleGetNotifications(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	query := r.URL.Query()
+	userEmail := query.Get("userEmail")
+	if userEmail == "" {
+		logAndSendError(w, "userEmail is required", http.StatusBadRequest, nil)
+		return
+	}
+
+	notifications, err := listNotifications(userEmail, query.Get("unread") == "true")
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(notifications)
+}
+
+// HandleReadNotifications は通知を既読にする関数
+func HandleReadNotifications(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		logAndSendError(w, "Only PUT requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		UserEmail       string   `json:"userEmail"`
+		NotificationIds []string `json:"notificationIds"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+
+	// 他のユーザーの通知は更新しないよう userEmail も条件に含める
+	if len(data.NotificationIds) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(data.NotificationIds)), ", ")
+		params := []interface{}{data.UserEmail}
+		for _, id := range data.NotificationIds {
+			params = append(params, id)
+		}
+		_, err := database.Db.Exec("UPDATE notifications SET isRead = TRUE WHERE userEmail = ? AND id IN ("+placeholders+")", params...)
+		if err != nil {
+			logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	responseData := map[string]string{"message": "既読にしました"}
+	json.NewEncoder(w).Encode(responseData)
+}
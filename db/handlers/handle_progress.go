@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"db/database"
+	"encoding/json"
+	"net/http"
+)
+
+// HandleGetProgress はユーザーのコース内での学習の進捗をカテゴリ・章ごとに返す関数
+func HandleGetProgress(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	query := r.URL.Query()
+	courseID := query.Get("courseId")
+	userEmail := query.Get("userEmail")
+	if !requireCourseMember(w, courseID, userEmail) {
+		return
+	}
+
+	progress, err := courseProgress(courseID, userEmail)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(progress)
+}
+
+// HandleSetProgress はアイテムの学習完了を記録（completed: true）・取り消し（false）する関数
+func HandleSetProgress(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		logAndSendError(w, "Only PUT requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		CourseID  string `json:"courseId"`
+		ItemID    string `json:"itemId"`
+		UserEmail string `json:"userEmail"`
+		Completed bool   `json:"completed"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+	if !requireCourseMember(w, data.CourseID, data.UserEmail) {
+		return
+	}
+
+	item, ok := findAccessibleItem(w, data.CourseID, data.ItemID, data.UserEmail)
+	if !ok {
+		return
+	}
+
+	query := "INSERT IGNORE INTO item_progress (userEmail, itemId) VALUES (?, ?)"
+	if !data.Completed {
+		query = "DELETE FROM item_progress WHERE userEmail = ? AND itemId = ?"
+	}
+	if _, err := database.Db.Exec(query, data.UserEmail, item.ID); err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	responseData := map[string]interface{}{"itemId": item.ID, "completed": data.Completed}
+	json.NewEncoder(w).Encode(responseData)
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"db/database"
+	"db/model"
+	"encoding/json"
+	"net/http"
+)
+
+// HandleGetQuiz はアイテムの小テストの問題を返す関数
+// 正解は問題を編集できるユーザーにのみ返す
+func HandleGetQuiz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	query := r.URL.Query()
+	userEmail := query.Get("userEmail")
+	item, ok := findAccessibleItem(w, query.Get("courseId"), query.Get("itemId"), userEmail)
+	if !ok {
+		return
+	}
+
+	editable, err := canEditQuiz(item, userEmail)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+	questions, err := listQuizQuestions(item.ID, editable)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	responseData := map[string]interface{}{"itemId": item.ID, "questions": questions, "editable": editable, "passPercentage": quizPassPercentage}
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// HandleSaveQuiz はアイテムの作成者・コースの講師が小テストの問題をまとめて置き換える関数
+func HandleSaveQuiz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		logAndSendError(w, "Only PUT requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		CourseID  string               `json:"courseId"`
+		ItemID    string               `json:"itemId"`
+		UserEmail string               `json:"userEmail"`
+		Questions []model.QuizQuestion `json:"questions"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+
+	item, ok := findAccessibleItem(w, data.CourseID, data.ItemID, data.UserEmail)
+	if !ok {
+		return
+	}
+	if editable, err := canEditQuiz(item, data.UserEmail); err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	} else if !editable {
+		logAndSendError(w, "Only the author or course staff can edit the quiz", http.StatusForbidden, nil)
+		return
+	}
+
+	tx, err := database.Db.Begin()
+	if err != nil {
+		logAndSendError(w, "Failed to begin transaction", http.StatusInternalServerError, err)
+		return
+	}
+	defer tx.Rollback()
+
+	err = saveQuizQuestions(tx, item.ID, data.Questions)
+	if err == errInvalidQuestion {
+		logAndSendError(w, "Invalid quiz question", http.StatusBadRequest, err)
+		return
+	}
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		logAndSendError(w, "Failed to commit transaction", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	responseData := map[string]string{"message": "更新が成功しました"}
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// HandleSubmitQuiz は小テストの解答を採点して保存する関数
+// 結果は問題ごとの正誤のみを返し、正解は返さない
+func HandleSubmitQuiz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		logAndSendError(w, "Only POST requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		CourseID  string       `json:"courseId"`
+		ItemID    string       `json:"itemId"`
+		UserEmail string       `json:"userEmail"`
+		Answers   []QuizAnswer `json:"answers"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+	if !requireCourseMember(w, data.CourseID, data.UserEmail) {
+		return
+	}
+
+	item, ok := findAccessibleItem(w, data.CourseID, data.ItemID, data.UserEmail)
+	if !ok {
+		return
+	}
+	questions, err := listQuizQuestions(item.ID, true)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+	if len(questions) == 0 {
+		logAndSendError(w, "This item has no quiz", http.StatusNotFound, nil)
+		return
+	}
+
+	attempt := gradeQuiz(questions, data.Answers)
+	attempt.ItemID = item.ID
+	attempt.UserEmail = data.UserEmail
+	if err := saveQuizAttempt(&attempt); err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(attempt)
+}
+
+// HandleGetQuizAttempts はユーザーの小テストの解答履歴を返す関数
+func HandleGetQuizAttempts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	query := r.URL.Query()
+	userEmail := query.Get("userEmail")
+	if !requireCourseMember(w, query.Get("courseId"), userEmail) {
+		return
+	}
+	item, ok := findAccessibleItem(w, query.Get("courseId"), query.Get("itemId"), userEmail)
+	if !ok {
+		return
+	}
+
+	attempts, err := listQuizAttempts(item.ID, userEmail)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(attempts)
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"db/database"
+	"encoding/json"
+	"net/http"
+)
+
+// HandleRateItem はアイテムを1〜5で評価（PUT）・評価を取り消し（DELETE）する関数
+// 評価はユーザーごとに1件で、再度評価すると上書きされる
+func HandleRateItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut && r.Method != http.MethodDelete {
+		logAndSendError(w, "Only PUT and DELETE requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		CourseID  string `json:"courseId"`
+		ItemID    string `json:"itemId"`
+		UserEmail string `json:"userEmail"`
+		Score     int    `json:"score"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+	if data.UserEmail == "" {
+		logAndSendError(w, "userEmail is required", http.StatusBadRequest, nil)
+		return
+	}
+	if r.Method == http.MethodPut && (data.Score < 1 || data.Score > 5) {
+		logAndSendError(w, "Score must be between 1 and 5", http.StatusBadRequest, nil)
+		return
+	}
+
+	item, ok := findAccessibleItem(w, data.CourseID, data.ItemID, data.UserEmail)
+	if !ok {
+		return
+	}
+	if item.CreatedBy == data.UserEmail {
+		logAndSendError(w, "You cannot rate your own item", http.StatusForbidden, nil)
+		return
+	}
+
+	tx, err := database.Db.Begin()
+	if err != nil {
+		logAndSendError(w, "Failed to begin transaction", http.StatusInternalServerError, err)
+		return
+	}
+	defer tx.Rollback()
+
+	// 同じアイテムへの評価を直列化し、集計値が古い件数で上書きされないようにする
+	if _, err := tx.Exec("SELECT id FROM items WHERE id = ? FOR UPDATE", item.ID); err != nil {
+		logAndSendError(w, "Failed to lock item", http.StatusInternalServerError, err)
+		return
+	}
+
+	if r.Method == http.MethodPut {
+		_, err = tx.Exec("INSERT INTO item_ratings (itemId, userEmail, score) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE score = VALUES(score)",
+			item.ID, data.UserEmail, data.Score)
+	} else {
+		_, err = tx.Exec("DELETE FROM item_ratings WHERE itemId = ? AND userEmail = ?", item.ID, data.UserEmail)
+	}
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+
+	// 検索で並べ替え・絞り込みに使うため、平均と件数をitemsに保存する（更新日時は変えない）
+	_, err = tx.Exec(`
+		UPDATE items SET
+			ratingAvg = (SELECT COALESCE(AVG(score), 0) FROM item_ratings WHERE itemId = ?),
+			ratingCount = (SELECT COUNT(*) FROM item_ratings WHERE itemId = ?),
+			updatedAt = updatedAt
+		WHERE id = ?`, item.ID, item.ID, item.ID)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+
+	var avg float64
+	var count int
+	if err := tx.QueryRow("SELECT ratingAvg, ratingCount FROM items WHERE id = ?", item.ID).Scan(&avg, &count); err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		logAndSendError(w, "Failed to commit transaction", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	responseData := map[string]interface{}{"itemId": item.ID, "ratingAvg": avg, "ratingCount": count}
+	json.NewEncoder(w).Encode(responseData)
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"db/database"
+	"encoding/json"
+	"net/http"
+)
+
+// HandleLikeItem はアイテムへのいいねを付ける・外す関数
+// 状態を指定する方式のため、同じリクエストを繰り返しても結果は変わらない
+func HandleLikeItem(w http.ResponseWriter, r *http.Request) {
+	setReaction(w, r, "item_likes")
+}
+
+// HandleBookmarkItem はアイテムのブックマークを付ける・外す関数
+func HandleBookmarkItem(w http.ResponseWriter, r *http.Request) {
+	setReaction(w, r, "item_bookmarks")
+}
+
+// setReaction はいいね・ブックマークのテーブルに呼び出したユーザーの状態を保存する
+func setReaction(w http.ResponseWriter, r *http.Request, table string) {
+	if r.Method != http.MethodPut {
+		logAndSendError(w, "Only PUT requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		CourseID  string `json:"courseId"`
+		ItemID    string `json:"itemId"`
+		UserEmail string `json:"userEmail"`
+		Active    bool   `json:"active"` // true: 付ける、false: 外す
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+	if data.UserEmail == "" {
+		logAndSendError(w, "userEmail is required", http.StatusBadRequest, nil)
+		return
+	}
+
+	// 閲覧できないアイテムには付けられない
+	item, ok := findAccessibleItem(w, data.CourseID, data.ItemID, data.UserEmail)
+	if !ok {
+		return
+	}
+
+	query := "INSERT IGNORE INTO " + table + " (itemId, userEmail) VALUES (?, ?)"
+	if !data.Active {
+		query = "DELETE FROM " + table + " WHERE itemId = ? AND userEmail = ?"
+	}
+	if _, err := database.Db.Exec(query, item.ID, data.UserEmail); err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+
+	var count int
+	if err := database.Db.QueryRow("SELECT COUNT(*) FROM "+table+" WHERE itemId = ?", item.ID).Scan(&count); err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	responseData := map[string]interface{}{"itemId": item.ID, "active": data.Active, "count": count}
+	json.NewEncoder(w).Encode(responseData)
+}
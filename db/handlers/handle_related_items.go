@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"db/model"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// HandleGetRelatedItems はアイテムの関連アイテムを類似度の高い順に返す関数
+// 関連アイテムはアイテムの追加・更新時に計算して保存しておき、未計算の場合はここで計算する
+func HandleGetRelatedItems(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	query := r.URL.Query()
+	userEmail := query.Get("userEmail")
+	item, ok := findAccessibleItem(w, query.Get("courseId"), query.Get("itemId"), userEmail)
+	if !ok {
+		return
+	}
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit < 1 || limit > maxRelatedItems {
+		limit = 5
+	}
+
+	if computed, err := hasRelatedItems(item.ID); err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	} else if !computed {
+		if err := computeRelatedItems(item.ID); err != nil {
+			logAndSendError(w, "Failed to compute related items", http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	related, err := relatedItems(item.ID, userEmail, limit)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+
+	// サムネイルのURLといいね・ブックマークの状態を設定
+	items := make([]model.Item, len(related))
+	for i, r := range related {
+		items[i] = r.Item
+	}
+	if err := decorateItems(items, userEmail); err != nil {
+		logAndSendError(w, "Failed to load item details", http.StatusInternalServerError, err)
+		return
+	}
+	for i := range related {
+		related[i].Item = items[i]
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(related)
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"db/markdown"
+	"encoding/json"
+	"net/http"
+)
+
+// maxPreviewLength はプレビューできる本文の最大バイト数
+const maxPreviewLength = 1 << 20
+
+// HandleRenderMarkdown は編集中の本文（Markdown）を HTML と目次に変換して返す関数（プレビュー用）
+// 添付ファイルのキーは署名付きURLに書き換えない
+func HandleRenderMarkdown(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		logAndSendError(w, "Only POST requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		Content string `json:"content"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxPreviewLength+1024)).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+	if len(data.Content) > maxPreviewLength {
+		logAndSendError(w, "Content is too long", http.StatusRequestEntityTooLarge, nil)
+		return
+	}
+
+	result := markdown.Render(data.Content, markdown.Options{})
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// HandleReviewQueue はレビュー待ちのアイテムを依頼の古い順に返す関数
+func HandleReviewQueue(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	query := r.URL.Query()
+	userEmail := query.Get("userEmail")
+	courseID := query.Get("courseId")
+	if !isReviewer(userEmail) {
+		logAndSendError(w, "Only reviewers can see the review queue", http.StatusForbidden, nil)
+		return
+	}
+	if courseID == "" {
+		logAndSendError(w, "courseId is required", http.StatusBadRequest, nil)
+		return
+	}
+
+	// 自分が作成したアイテムは自分でレビューできないため除外
+	items, err := queryItems("SELECT "+itemColumns+" FROM items WHERE courseId = ? AND status = ? AND createdBy <> ? ORDER BY updatedAt",
+		courseID, statusInReview, userEmail)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+	if err := decorateItems(items, userEmail); err != nil {
+		logAndSendError(w, "Failed to load item details", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(items)
+}
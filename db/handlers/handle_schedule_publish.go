@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"database/sql"
+	"db/database"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// HandleSchedulePublish はアイテムの公開日時を予約する関数
+// 承認前のアイテムは日時を保存するだけで、承認時に予約公開になる
+// 承認済みの予約公開で publishAt を省略すると予約を取り消して下書きに戻す（再度レビューが必要）
+func HandleSchedulePublish(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		logAndSendError(w, "Only POST requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		ItemID    string     `json:"itemId"`
+		UserEmail string     `json:"userEmail"`
+		PublishAt *time.Time `json:"publishAt"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+
+	item, err := findItem(data.ItemID)
+	if err == sql.ErrNoRows {
+		logAndSendError(w, "Item not found", http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+
+	// 作成者以外は変更できない
+	if data.UserEmail == "" || item.CreatedBy != data.UserEmail {
+		logAndSendError(w, "Only the author can schedule publishing", http.StatusForbidden, nil)
+		return
+	}
+	switch item.Status {
+	case statusDraft, statusInReview, statusRejected, statusScheduled:
+	default:
+		logAndSendError(w, "Only unpublished items can be scheduled", http.StatusConflict, nil)
+		return
+	}
+
+	status := item.Status
+	var publishAt interface{}
+	if data.PublishAt != nil {
+		if !data.PublishAt.After(time.Now()) {
+			logAndSendError(w, "publishAt must be in the future", http.StatusBadRequest, nil)
+			return
+		}
+		publishAt = data.PublishAt.UTC().Format("2006-01-02 15:04:05")
+	} else if item.Status == statusScheduled {
+		status = statusDraft
+	}
+
+	// 読み込んだ後に承認・公開などで状態が変わっていた場合は更新しない
+	result, err := database.Db.Exec("UPDATE items SET status = ?, publishAt = ?, updatedAt = NOW() WHERE id = ? AND status = ?",
+		status, publishAt, item.ID, item.Status)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+	if n == 0 {
+		// 同じ秒に同じ値で更新した場合も0件になるため、状態が変わったかどうかを確かめる
+		var current string
+		if err := database.Db.QueryRow("SELECT status FROM items WHERE id = ?", item.ID).Scan(&current); err != nil && err != sql.ErrNoRows {
+			logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+			return
+		}
+		if current != item.Status {
+			logAndSendError(w, "Item status has changed, please reload", http.StatusConflict, nil)
+			return
+		}
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	responseData := map[string]interface{}{"id": item.ID, "status": status, "publishAt": data.PublishAt}
+	json.NewEncoder(w).Encode(responseData)
+}
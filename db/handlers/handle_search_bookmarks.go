@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// HandleSearchMyBookmarks はブックマークしたアイテムを検索と同じ条件で絞り込んで返す関数
+func HandleSearchMyBookmarks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		logAndSendError(w, "Only POST requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var queryData searchFilter
+	if err := json.NewDecoder(r.Body).Decode(&queryData); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+	if queryData.CourseID == "" || queryData.UserEmail == "" {
+		logAndSendError(w, "courseId and userEmail are required", http.StatusBadRequest, nil)
+		return
+	}
+
+	// 検索できるのはコースのメンバーのみ
+	if !requireCourseMember(w, queryData.CourseID, queryData.UserEmail) {
+		return
+	}
+
+	// ブックマーク後に閲覧できなくなったアイテムは含めない
+	readableSQL, readableParams := readableItemsCondition(queryData.UserEmail)
+	conditions := []string{readableSQL, "id IN (SELECT itemId FROM item_bookmarks WHERE userEmail = ?)"}
+	params := append(readableParams, queryData.UserEmail)
+	sqlQuery, params := buildSearchQuery(queryData, conditions, params)
+
+	// SQLクエリを実行
+	items, err := queryItems(sqlQuery, params...)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+
+	// サムネイルのURLといいね・ブックマークの状態を設定
+	if err := decorateItems(items, queryData.UserEmail); err != nil {
+		logAndSendError(w, "Failed to load item details", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(items)
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"db/markdown"
+	"encoding/json"
+	"net/http"
+)
+
+// HandleGetItemSnippets はアイテムの本文のコードブロックを、言語と開始行付きで出現順に返す関数
+// language を指定した場合はその言語のものだけを返す
+func HandleGetItemSnippets(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	query := r.URL.Query()
+	item, ok := findAccessibleItem(w, query.Get("courseId"), query.Get("itemId"), query.Get("userEmail"))
+	if !ok {
+		return
+	}
+
+	language := markdown.NormalizeLanguage(query.Get("language"))
+	snippets := []markdown.CodeBlock{}
+	for _, block := range markdown.CodeBlocks(item.Content) {
+		if language == "" || block.Language == language {
+			snippets = append(snippets, block)
+		}
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	responseData := map[string]interface{}{"itemId": item.ID, "snippets": snippets}
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// HandleGetSnippetLanguages はコース内のコードで使われている言語を、アイテム数付きで返す関数（検索の絞り込みの選択肢用）
+func HandleGetSnippetLanguages(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	query := r.URL.Query()
+	courseID := query.Get("courseId")
+	if courseID == "" {
+		logAndSendError(w, "courseId is required", http.StatusBadRequest, nil)
+		return
+	}
+
+	languages, err := snippetLanguages(courseID, query.Get("userEmail"))
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(languages)
+}
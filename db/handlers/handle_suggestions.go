@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// HandleSuggestTags はタイトルと本文から、タグ・カテゴリ・章の候補を返す関数
+// コース内の既存のアイテムだけを使って計算し、外部のサービスには送信しない
+func HandleSuggestTags(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		logAndSendError(w, "Only POST requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		CourseID  string `json:"courseId"`
+		UserEmail string `json:"userEmail"`
+		Title     string `json:"title"`
+		Content   string `json:"content"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+	if strings.TrimSpace(data.Title) == "" && strings.TrimSpace(data.Content) == "" {
+		logAndSendError(w, "Required fields are missing", http.StatusBadRequest, nil)
+		return
+	}
+	if !requireCourseMember(w, data.CourseID, data.UserEmail) {
+		return
+	}
+
+	docs, err := loadCourseDocuments(data.CourseID, data.UserEmail)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+	suggestions := suggestFromContent(docs, data.Title, data.Content)
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(suggestions)
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// タグの候補として一度に返す数の既定値と上限
+const (
+	defaultTagSuggestions = 10
+	maxTagSuggestions     = 50
+)
+
+// HandleGetTags は入力途中のタグに前方一致するタグを使用数付きで返す関数（prefix が空の場合はよく使われるタグ）
+func HandleGetTags(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	query := r.URL.Query()
+	courseID := query.Get("courseId")
+	if courseID == "" {
+		logAndSendError(w, "courseId is required", http.StatusBadRequest, nil)
+		return
+	}
+	// タグの候補を見られるのはコースのメンバーのみ
+	userEmail := query.Get("userEmail")
+	if !requireCourseMember(w, courseID, userEmail) {
+		return
+	}
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit < 1 {
+		limit = defaultTagSuggestions
+	}
+	limit = min(limit, maxTagSuggestions)
+
+	tags, err := suggestTags(courseID, userEmail, query.Get("prefix"), limit)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(tags)
+}
+
+// HandleMergeTags は管理者が複数のタグを1つにまとめる関数
+func HandleMergeTags(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		logAndSendError(w, "Only POST requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		UserEmail string   `json:"userEmail"`
+		CourseID  string   `json:"courseId"`
+		Sources   []string `json:"sources"`
+		Target    string   `json:"target"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+	changeTags(w, data.UserEmail, data.CourseID, data.Sources, data.Target)
+}
+
+// HandleRenameTag は管理者がタグの名前を変更する関数
+// 変更後の名前のタグがすでにある場合はそのタグにまとめる
+func HandleRenameTag(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		logAndSendError(w, "Only PUT requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		UserEmail string `json:"userEmail"`
+		CourseID  string `json:"courseId"`
+		Name      string `json:"name"`
+		NewName   string `json:"newName"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+	changeTags(w, data.UserEmail, data.CourseID, []string{data.Name}, data.NewName)
+}
+
+// changeTags はタグのまとめ・名前の変更を検証して実行し、レスポンスを返す
+func changeTags(w http.ResponseWriter, userEmail, courseID string, sources []string, target string) {
+	if !isAdmin(userEmail) {
+		logAndSendError(w, "Only administrators can change tags", http.StatusForbidden, nil)
+		return
+	}
+	normalizedSources, err := normalizeTags(sources)
+	if err != nil {
+		logAndSendError(w, "Invalid tags", http.StatusBadRequest, err)
+		return
+	}
+	target = normalizeTag(target)
+	if courseID == "" || target == "" || len(normalizedSources) == 0 {
+		logAndSendError(w, "Required fields are missing", http.StatusBadRequest, nil)
+		return
+	}
+	if targets, err := normalizeTags([]string{target}); err != nil || len(targets) == 0 {
+		logAndSendError(w, "Invalid tags", http.StatusBadRequest, err)
+		return
+	}
+
+	moved, err := mergeTags(courseID, normalizedSources, target)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	responseData := map[string]interface{}{"target": target, "moved": moved}
+	json.NewEncoder(w).Encode(responseData)
+}
@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"database/sql"
 	"db/database"
 	"db/model"
 	"encoding/json"
+	"log"
 	"net/http"
 )
 
@@ -27,15 +29,92 @@ func HandleUpdateItems(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 更新できるのはアイテムのコースのメンバーのみ（コースの移動はできない）
+	current, err := findItem(data.ID)
+	if err == sql.ErrNoRows {
+		logAndSendError(w, "Item not found", http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+	if !requireCourseMember(w, current.CourseID, data.CreatedBy) {
+		return
+	}
+	// 他のユーザーのアイテムを更新できるのはコースの講師のみ
+	isAuthor := current.CreatedBy == data.CreatedBy
+	if !isAuthor {
+		staff, err := isCourseStaff(current.CourseID, data.CreatedBy)
+		if err != nil {
+			logAndSendError(w, "Failed to check course membership", http.StatusInternalServerError, err)
+			return
+		}
+		if !staff {
+			logAndSendError(w, "Only the author or course instructors can update this item", http.StatusForbidden, nil)
+			return
+		}
+		// 講師が更新した場合も作成者の表示名は変えない
+		data.CreatedByName = current.CreatedByName
+	}
+	if ok, err := courseHasCategory(current.CourseID, data.Category, data.Chapter); err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	} else if !ok {
+		logAndSendError(w, "Unknown category or chapter", http.StatusBadRequest, nil)
+		return
+	}
+
+	// 公開範囲は指定された場合のみ検証して更新する（グループも合わせて置き換える）
+	// グループは講師が更新する場合も作成者がメンバーであるものに限る
+	var visibilityGroupValue interface{}
+	if data.Visibility != "" {
+		if err := checkVisibility(data.Visibility, data.VisibilityGroup, current.CreatedBy); err != nil {
+			sendVisibilityError(w, err)
+			return
+		}
+		if data.VisibilityGroup != "" {
+			visibilityGroupValue = data.VisibilityGroup
+		}
+	}
+
+	tags, err := normalizeTags(data.Tags)
+	if err != nil {
+		logAndSendError(w, "Invalid tags", http.StatusBadRequest, err)
+		return
+	}
+
+	// 禁止語を含む内容には更新できない
+	if !rejectBannedWords(w, data.Title, data.Content, tags) {
+		return
+	}
+
+	// 重複判定用のハッシュは更新前に求めておく（ファイルが差し替えられた場合のみファイルを読み直す）
+	fingerprint, fingerprintErr := computeUpdatedFingerprint(data.ID, data.Title, data.Content, data.File, current.File)
+
+	// 公開中・予約公開・レビュー待ちのアイテムを編集した場合はレビュー待ちに戻す
+	status := statusAfterEdit(current, data.CreatedBy)
+
+	// アイテム・タグ・サムネイルの削除は同じトランザクションで保存する
+	tx, err := database.Db.Begin()
+	if err != nil {
+		logAndSendError(w, "Failed to begin transaction", http.StatusInternalServerError, err)
+		return
+	}
+	defer tx.Rollback()
+
 	// アイテムを更新するSQLクエリを作成
-	stmt, err := database.Db.Prepare(`
+	stmt, err := tx.Prepare(`
 		UPDATE items 
 		SET title = ?, content = ?, category = ?, chapter = ?, 
 			file = IF(LENGTH(?) > 0, ?, file), 
 			fileType = IF(LENGTH(?) > 0, ?, fileType), 
+			visibility = IF(LENGTH(?) > 0, ?, visibility), 
+			visibilityGroup = IF(LENGTH(?) > 0, ?, visibilityGroup), 
 			createdByName = ?, 
+			status = ?, 
 			updatedAt = NOW() 
-		WHERE id = ?`)
+		WHERE id = ? AND courseId = ?`)
 	if err != nil {
 		logAndSendError(w, "Failed to prepare SQL statement", http.StatusInternalServerError, err)
 		return
@@ -46,17 +125,54 @@ func HandleUpdateItems(w http.ResponseWriter, r *http.Request) {
 		data.Title, data.Content, data.Category, data.Chapter,
 		data.File, data.File, // IF(LENGTH(?) > 0, ?, file)
 		data.FileType, data.FileType, // IF(LENGTH(?) > 0, ?, fileType)
-		data.CreatedByName, data.ID,
+		data.Visibility, data.Visibility, // IF(LENGTH(?) > 0, ?, visibility)
+		data.Visibility, visibilityGroupValue, // IF(LENGTH(?) > 0, ?, visibilityGroup)
+		data.CreatedByName, status, data.ID, current.CourseID,
 	)
 	if err != nil {
 		logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
 		return
 	}
 
+	// タグは指定された場合のみ置き換える
+	if data.Tags != nil {
+		if err := setItemTags(tx, current.CourseID, data.ID, tags); err != nil {
+			logAndSendError(w, "Failed to save tags", http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	// ファイルが差し替えられた場合はサムネイルを作り直す
+	if data.File != "" {
+		if _, err := tx.Exec("DELETE FROM thumbnails WHERE itemId = ?", data.ID); err != nil {
+			logAndSendError(w, "Failed to execute SQL statement", http.StatusInternalServerError, err)
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		logAndSendError(w, "Failed to commit transaction", http.StatusInternalServerError, err)
+		return
+	}
+
+	// 重複判定用のハッシュとコードブロックは検索用の情報のため、更新が済んだ後は失敗してもログに残すだけにする
+	if fingerprintErr != nil {
+		log.Printf("Error: failed to compute fingerprint for %s: %v\n", data.ID, fingerprintErr)
+	} else if err := saveFingerprint(data.ID, fingerprint); err != nil {
+		log.Printf("Error: failed to save fingerprint for %s: %v\n", data.ID, err)
+	}
+	if err := saveItemSnippets(data.ID, data.Content); err != nil {
+		log.Printf("Error: failed to save code snippets for %s: %v\n", data.ID, err)
+	}
+
+	if data.File != "" {
+		go generateThumbnails(data.ID, data.File, data.FileType)
+	}
+	go refreshRelatedItems(data.ID)
+
 	// 成功時のレスポンスを返す
 	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	responseData := map[string]string{"message": "更新が成功しました"}
+	responseData := map[string]string{"message": "更新が成功しました", "status": status}
 	json.NewEncoder(w).Encode(responseData)
 }
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"bytes"
+	"db/storage"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// アップロードできるファイルサイズの上限（32MB）
+const maxUploadSize = 32 << 20
+
+// activeFileExtensions はブラウザでスクリプトを実行できるため、アップロードを受け付けないファイルの拡張子
+var activeFileExtensions = map[string]bool{
+	".html": true, ".htm": true, ".xhtml": true, ".shtml": true,
+	".svg": true, ".svgz": true, ".xml": true, ".xsl": true, ".xslt": true,
+	".js": true, ".mjs": true,
+}
+
+// isActiveContent はファイル名か内容がスクリプトを実行できる種類かどうかを判定する
+func isActiveContent(name string, head []byte) bool {
+	if activeFileExtensions[strings.ToLower(path.Ext(name))] {
+		return true
+	}
+	mediaType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	return mediaType == "text/html" || mediaType == "text/xml" || mediaType == "image/svg+xml"
+}
+
+// HandleUploadFile はmultipart/form-dataで送られたファイルをストレージに保存する関数
+func HandleUploadFile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		logAndSendError(w, "Only POST requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		logAndSendError(w, "Failed to read uploaded file", http.StatusBadRequest, err)
+		return
+	}
+	defer file.Close()
+
+	id, err := generateULID()
+	if err != nil {
+		logAndSendError(w, "Failed to generate ULID", http.StatusInternalServerError, err)
+		return
+	}
+
+	// ファイル名からパス区切りを取り除いてキーを作成
+	name := path.Base(strings.ReplaceAll(header.Filename, "\\", "/"))
+	if name == "." || name == "/" || name == ".." {
+		name = "file"
+	}
+	key := storedFilePrefix + id + "/" + name
+
+	// 拡張子と先頭の内容から、HTML・SVG・XML・JavaScript のファイルを拒否する
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		logAndSendError(w, "Failed to read uploaded file", http.StatusBadRequest, err)
+		return
+	}
+	head = head[:n]
+	if isActiveContent(name, head) {
+		logAndSendError(w, "This file type is not allowed", http.StatusUnsupportedMediaType, nil)
+		return
+	}
+
+	if err := storage.Store.Put(key, io.MultiReader(bytes.NewReader(head), file)); err != nil {
+		logAndSendError(w, "Failed to store file", http.StatusInternalServerError, err)
+		return
+	}
+
+	// 成功時のレスポンスを返す
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	responseData := map[string]string{
+		"file":     key,
+		"fileType": header.Header.Get("Content-Type"),
+		"url":      fileURL(key),
+	}
+	json.NewEncoder(w).Encode(responseData)
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"db/model"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// HandleRecordView はアイテムの閲覧を記録する関数
+// 同じユーザー（未ログインの場合は同じIPアドレス）の短時間での再閲覧は数えない
+func HandleRecordView(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		logAndSendError(w, "Only POST requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	// リクエストボディからデータをデコード
+	var data struct {
+		CourseID  string `json:"courseId"`
+		ItemID    string `json:"itemId"`
+		UserEmail string `json:"userEmail"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logAndSendError(w, "Failed to decode request body", http.StatusBadRequest, err)
+		return
+	}
+
+	item, ok := findAccessibleItem(w, data.CourseID, data.ItemID, data.UserEmail)
+	if !ok {
+		return
+	}
+
+	viewerKey := "user:" + data.UserEmail
+	if data.UserEmail == "" {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+		viewerKey = "ip:" + host
+	}
+
+	counted, err := recordView(item.ID, viewerKey)
+	if err != nil {
+		logAndSendError(w, "Failed to record view", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	responseData := map[string]interface{}{"itemId": item.ID, "counted": counted}
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// HandleTrendingItems は閲覧数を時間で減衰させたスコアでカテゴリごとにランキングを返す関数
+// category を指定した場合はそのカテゴリのランキングのみを返す
+func HandleTrendingItems(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		logAndSendError(w, "Only GET requests are allowed", http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	query := r.URL.Query()
+	f := searchFilter{
+		CourseID:  query.Get("courseId"),
+		Category:  query.Get("category"),
+		Chapter:   query.Get("chapter"),
+		UserEmail: query.Get("userEmail"),
+	}
+	if f.CourseID == "" {
+		logAndSendError(w, "courseId is required", http.StatusBadRequest, nil)
+		return
+	}
+
+	// ランキングを見られるのはコースのメンバーのみ
+	if !requireCourseMember(w, f.CourseID, f.UserEmail) {
+		return
+	}
+
+	// 集計期間（日）・半減期（日）・カテゴリごとの件数
+	days, err := strconv.Atoi(query.Get("days"))
+	if err != nil || days < 1 || days > 365 {
+		days = 14
+	}
+	halfLife, err := strconv.ParseFloat(query.Get("halfLife"), 64)
+	if err != nil || halfLife <= 0 {
+		halfLife = 3
+	}
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	trending, err := trendingItems(f, days, halfLife)
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return
+	}
+
+	// スコアの高い順に並んでいるため、カテゴリごとに先頭から limit 件を取る
+	var selected []TrendingItem
+	counts := map[string]int{}
+	for _, t := range trending {
+		if counts[t.Category] < limit {
+			counts[t.Category]++
+			selected = append(selected, t)
+		}
+	}
+
+	// サムネイルのURLといいね・ブックマークの状態を設定
+	items := make([]model.Item, len(selected))
+	for i, t := range selected {
+		items[i] = t.Item
+	}
+	if err := decorateItems(items, f.UserEmail); err != nil {
+		logAndSendError(w, "Failed to load item details", http.StatusInternalServerError, err)
+		return
+	}
+	ranking := map[string][]TrendingItem{}
+	for i, t := range selected {
+		t.Item = items[i]
+		ranking[t.Category] = append(ranking[t.Category], t)
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(ranking)
+}
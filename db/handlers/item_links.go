@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"database/sql"
+	"db/database"
+	"db/model"
+	"errors"
+	"strings"
+	"time"
+)
+
+// アイテム間の関係の種類
+const (
+	linkPrerequisite = "prerequisite"
+	linkRelated      = "related"
+	linkSupersedes   = "supersedes"
+)
+
+var (
+	errSelfLink          = errors.New("an item cannot link to itself")
+	errPrerequisiteCycle = errors.New("prerequisite links cannot form a cycle")
+)
+
+func isValidLinkType(t string) bool {
+	switch t {
+	case linkPrerequisite, linkRelated, linkSupersedes:
+		return true
+	}
+	return false
+}
+
+// scanItemLink は itemId, targetId, linkType, createdBy, createdAt, 関係の先のアイテムのタイトルの順に読み込んだ行を model.ItemLink に変換する
+func scanItemLink(s scanner) (model.ItemLink, error) {
+	var l model.ItemLink
+	var createdAtStr string
+	if err := s.Scan(&l.ItemID, &l.TargetID, &l.Type, &l.CreatedBy, &createdAtStr, &l.TargetTitle); err != nil {
+		return l, err
+	}
+	var err error
+	l.CreatedAt, err = time.Parse("2006-01-02 15:04:05", createdAtStr)
+	return l, err
+}
+
+// listItemLinks はアイテムから出ている関係と、アイテムに向いている関係を返す
+// 相手のアイテムが閲覧できない関係は含めない
+func listItemLinks(itemID, userEmail string) (outgoing, incoming []model.ItemLink, err error) {
+	readableSQL, readableParams := readableItemsCondition(userEmail)
+	query := func(column, other string) ([]model.ItemLink, error) {
+		rows, err := database.Db.Query(`
+			SELECT l.itemId, l.targetId, l.linkType, l.createdBy, l.createdAt, items.title
+			FROM item_links l JOIN items ON items.id = l.`+other+`
+			WHERE l.`+column+` = ? AND `+readableSQL+`
+			ORDER BY l.linkType, l.createdAt`, append([]interface{}{itemID}, readableParams...)...)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		links := []model.ItemLink{}
+		for rows.Next() {
+			l, err := scanItemLink(rows)
+			if err != nil {
+				return nil, err
+			}
+			links = append(links, l)
+		}
+		return links, rows.Err()
+	}
+
+	if outgoing, err = query("itemId", "targetId"); err != nil {
+		return nil, nil, err
+	}
+	if incoming, err = query("targetId", "itemId"); err != nil {
+		return nil, nil, err
+	}
+	// アイテムに向いている関係で結合しているのは関係の元のアイテムのため、タイトルを SourceTitle に移す
+	for i := range incoming {
+		incoming[i].SourceTitle, incoming[i].TargetTitle = incoming[i].TargetTitle, ""
+	}
+	return outgoing, incoming, nil
+}
+
+// addItemLink は関係を保存する
+// 前提関係は、追加すると循環する場合に errPrerequisiteCycle を返す
+func addItemLink(courseID string, link model.ItemLink) error {
+	if link.ItemID == link.TargetID {
+		return errSelfLink
+	}
+
+	tx, err := database.Db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// 同じコースの関係の追加を直列化し、同時に追加された2本の関係で循環ができないようにする
+	if _, err := tx.Exec("SELECT id FROM courses WHERE id = ? FOR UPDATE", courseID); err != nil {
+		return err
+	}
+
+	if link.Type == linkPrerequisite {
+		cycle, err := requiresTransitively(tx, link.TargetID, link.ItemID)
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return errPrerequisiteCycle
+		}
+	}
+
+	_, err = tx.Exec("INSERT IGNORE INTO item_links (itemId, targetId, linkType, createdBy) VALUES (?, ?, ?, ?)",
+		link.ItemID, link.TargetID, link.Type, link.CreatedBy)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// requiresTransitively は from の前提関係をたどって to に到達するかを判定する
+func requiresTransitively(tx *sql.Tx, from, to string) (bool, error) {
+	visited := map[string]bool{from: true}
+	frontier := []string{from}
+	for len(frontier) > 0 {
+		params := make([]interface{}, 0, len(frontier)+1)
+		params = append(params, linkPrerequisite)
+		for _, id := range frontier {
+			params = append(params, id)
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(frontier)), ", ")
+		rows, err := tx.Query("SELECT targetId FROM item_links WHERE linkType = ? AND itemId IN ("+placeholders+")", params...)
+		if err != nil {
+			return false, err
+		}
+
+		var next []string
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return false, err
+			}
+			if id == to {
+				rows.Close()
+				return true, nil
+			}
+			if !visited[id] {
+				visited[id] = true
+				next = append(next, id)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return false, err
+		}
+		frontier = next
+	}
+	return false, nil
+}
+
+// deleteItemLinks はアイテムが関係元・関係先になっている関係をすべて削除する
+func deleteItemLinks(itemID string) error {
+	_, err := database.Db.Exec("DELETE FROM item_links WHERE itemId = ? OR targetId = ?", itemID, itemID)
+	return err
+}
+
+// ChapterGraph は章のアイテムと、その間の関係
+// Order は前提関係を満たす学習順（循環がないため必ずすべてのアイテムを含む）
+type ChapterGraph struct {
+	Nodes []model.Item     `json:"nodes"`
+	Edges []model.ItemLink `json:"edges"`
+	Order []string         `json:"order"`
+}
+
+// chapterGraph は章の閲覧できるアイテムと、その間の関係を返す
+func chapterGraph(courseID, category, chapter, userEmail string) (ChapterGraph, error) {
+	graph := ChapterGraph{Nodes: []model.Item{}, Edges: []model.ItemLink{}, Order: []string{}}
+
+	readableSQL, readableParams := readableItemsCondition(userEmail)
+	params := append([]interface{}{courseID, category, chapter}, readableParams...)
+	nodes, err := queryItems("SELECT "+itemColumns+" FROM items WHERE courseId = ? AND category = ? AND chapter = ? AND "+readableSQL+" ORDER BY createdAt, id", params...)
+	if err != nil || len(nodes) == 0 {
+		return graph, err
+	}
+	graph.Nodes = nodes
+
+	inGraph := make(map[string]bool, len(nodes))
+	ids := make([]interface{}, 0, len(nodes))
+	for _, item := range nodes {
+		inGraph[item.ID] = true
+		ids = append(ids, item.ID)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(nodes)), ", ")
+	rows, err := database.Db.Query(`
+		SELECT l.itemId, l.targetId, l.linkType, l.createdBy, l.createdAt, items.title
+		FROM item_links l JOIN items ON items.id = l.targetId
+		WHERE l.itemId IN (`+placeholders+`) ORDER BY l.createdAt`, ids...)
+	if err != nil {
+		return graph, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		l, err := scanItemLink(rows)
+		if err != nil {
+			return graph, err
+		}
+		if inGraph[l.TargetID] {
+			graph.Edges = append(graph.Edges, l)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return graph, err
+	}
+
+	graph.Order = prerequisiteOrder(nodes, graph.Edges)
+	return graph, nil
+}
+
+// prerequisiteOrder は前提となるアイテムが先に来るように並べたアイテムのIDを返す
+// 前提関係のないアイテム同士は作成順のまま並べる
+func prerequisiteOrder(nodes []model.Item, edges []model.ItemLink) []string {
+	pending := map[string]int{}
+	dependents := map[string][]string{}
+	for _, e := range edges {
+		if e.Type != linkPrerequisite {
+			continue
+		}
+		pending[e.ItemID]++
+		dependents[e.TargetID] = append(dependents[e.TargetID], e.ItemID)
+	}
+
+	order := make([]string, 0, len(nodes))
+	done := map[string]bool{}
+	for len(order) < len(nodes) {
+		progressed := false
+		for _, item := range nodes {
+			if done[item.ID] || pending[item.ID] > 0 {
+				continue
+			}
+			done[item.ID] = true
+			order = append(order, item.ID)
+			for _, d := range dependents[item.ID] {
+				pending[d]--
+			}
+			progressed = true
+			break
+		}
+		if !progressed {
+			break
+		}
+	}
+	return order
+}
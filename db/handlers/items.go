@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"database/sql"
+	"db/database"
+	"db/model"
+	"net/http"
+	"time"
+)
+
+// itemsテーブルから読み込む列（scanItem の Scan と順番を合わせる）
+// いいね・ブックマーク・コメントの数はソートにも使えるよう別名を付けて取得する
+const itemColumns = "id, courseId, title, content, category, chapter, file, fileType, createdBy, createdByName, createdAt, updatedAt, visibility, visibilityGroup, status, publishAt, ratingAvg, ratingCount, hidden" +
+	", (SELECT COUNT(*) FROM item_likes WHERE item_likes.itemId = items.id) AS likeCount" +
+	", (SELECT COUNT(*) FROM item_bookmarks WHERE item_bookmarks.itemId = items.id) AS bookmarkCount" +
+	", (SELECT COUNT(*) FROM comments WHERE comments.itemId = items.id AND comments.deleted = FALSE) AS commentCount"
+
+// 公開範囲
+const (
+	visibilityPublic  = "public"  // 誰でも閲覧・ダウンロード可能
+	visibilityCourse  = "course"  // アイテムのコースのメンバーのみ（ログインしているだけでは閲覧できない）
+	visibilityGroup   = "group"   // visibilityGroup のグループのメンバーのみ
+	visibilityPrivate = "private" // 作成者のみ
+)
+
+// isValidVisibility は公開範囲の値が正しいかどうかを判定する
+func isValidVisibility(v string) bool {
+	switch v {
+	case visibilityPublic, visibilityCourse, visibilityGroup, visibilityPrivate:
+		return true
+	}
+	return false
+}
+
+// 公開状態
+const (
+	statusDraft     = "draft"     // 下書き（作成者のみ）
+	statusInReview  = "in_review" // レビュー待ち（作成者とレビュアーのみ）
+	statusRejected  = "rejected"  // 差し戻し（作成者のみ）
+	statusScheduled = "scheduled" // 予約公開（publishAt を過ぎると公開中として扱う）
+	statusPublished = "published" // 公開中
+	statusArchived  = "archived"  // アーカイブ済み（作成者のみ）
+)
+
+// scanner は *sql.Row と *sql.Rows の共通部分
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanItem は itemColumns の順に読み込んだ行を model.Item に変換する
+func scanItem(s scanner) (model.Item, error) {
+	var item model.Item
+	var createdAtStr string // DATETIME 型のデータを文字列として読み込む
+	var updatedAtStr string
+	var visibilityGroupStr sql.NullString
+	var publishAtStr sql.NullString
+	err := s.Scan(
+		&item.ID,
+		&item.CourseID,
+		&item.Title,
+		&item.Content,
+		&item.Category,
+		&item.Chapter,
+		&item.File,
+		&item.FileType,
+		&item.CreatedBy,
+		&item.CreatedByName,
+		&createdAtStr,
+		&updatedAtStr,
+		&item.Visibility,
+		&visibilityGroupStr,
+		&item.Status,
+		&publishAtStr,
+		&item.RatingAvg,
+		&item.RatingCount,
+		&item.Hidden,
+		&item.LikeCount,
+		&item.BookmarkCount,
+		&item.CommentCount,
+	)
+	if err != nil {
+		return item, err
+	}
+
+	// createdAt と updatedAt の文字列を time.Time に変換
+	if item.CreatedAt, err = time.Parse("2006-01-02 15:04:05", createdAtStr); err != nil {
+		return item, err
+	}
+	if item.UpdatedAt, err = time.Parse("2006-01-02 15:04:05", updatedAtStr); err != nil {
+		return item, err
+	}
+	item.VisibilityGroup = visibilityGroupStr.String
+	if publishAtStr.Valid {
+		publishAt, err := time.Parse("2006-01-02 15:04:05", publishAtStr.String)
+		if err != nil {
+			return item, err
+		}
+		item.PublishAt = &publishAt
+	}
+	return item, nil
+}
+
+// queryItems はアイテムを検索してスライスで返す
+func queryItems(query string, params ...interface{}) ([]model.Item, error) {
+	rows, err := database.Db.Query(query, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.Item
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// decorateItems はレスポンス用にサムネイルのURLと呼び出したユーザーのいいね・ブックマークの状態を設定する
+func decorateItems(items []model.Item, userEmail string) error {
+	if err := attachThumbnails(items); err != nil {
+		return err
+	}
+	if err := attachTags(items); err != nil {
+		return err
+	}
+	return attachReactions(items, userEmail)
+}
+
+// itemRelationTables はアイテムの削除時に合わせて削除する、itemId 列を持つテーブル
+// （moderation_log は監査のため削除しない）
+var itemRelationTables = []string{"thumbnails", "item_likes", "item_bookmarks", "comments", "item_ratings", "item_view_marks", "item_view_daily", "item_progress", "learning_path_items", "quiz_questions", "quiz_attempts", "item_tags", "item_related", "item_related_computed", "item_fingerprints", "item_snippets", "link_checks", "item_reports"}
+
+// deleteItemRelations はアイテムに紐づく行を削除する
+func deleteItemRelations(itemID string) error {
+	for _, table := range itemRelationTables {
+		if _, err := database.Db.Exec("DELETE FROM "+table+" WHERE itemId = ?", itemID); err != nil {
+			return err
+		}
+	}
+	if _, err := database.Db.Exec("DELETE FROM item_related WHERE relatedId = ?", itemID); err != nil {
+		return err
+	}
+	if err := deleteItemDecks(itemID); err != nil {
+		return err
+	}
+	return deleteItemLinks(itemID)
+}
+
+// findItem はIDを指定してアイテムを1件取得する
+func findItem(id string) (model.Item, error) {
+	return scanItem(database.Db.QueryRow("SELECT "+itemColumns+" FROM items WHERE id = ?", id))
+}
+
+// findCourseItem はコース内のアイテムを1件取得する（他のコースのアイテムは sql.ErrNoRows）
+func findCourseItem(courseID, id string) (model.Item, error) {
+	return scanItem(database.Db.QueryRow("SELECT "+itemColumns+" FROM items WHERE id = ? AND courseId = ?", id, courseID))
+}
+
+// findAccessibleItem はコース内の閲覧できるアイテムを取得する
+// 見つからない・閲覧できない場合はエラーレスポンスを返して false を返す
+func findAccessibleItem(w http.ResponseWriter, courseID, itemID, userEmail string) (model.Item, bool) {
+	item, err := findCourseItem(courseID, itemID)
+	if err == sql.ErrNoRows {
+		logAndSendError(w, "Item not found", http.StatusNotFound, err)
+		return item, false
+	}
+	if err != nil {
+		logAndSendError(w, "Failed to execute SQL query", http.StatusInternalServerError, err)
+		return item, false
+	}
+	allowed, err := canAccessItem(item, userEmail)
+	if err != nil {
+		logAndSendError(w, "Failed to check access", http.StatusInternalServerError, err)
+		return item, false
+	}
+	if !allowed {
+		// 閲覧できないアイテムは存在を知られないよう 404 を返す
+		logAndSendError(w, "Item not found", http.StatusNotFound, nil)
+		return item, false
+	}
+	return item, true
+}
+
+// requireItemAuthorOrStaff はユーザーがアイテムの作成者でもコースの講師でもない場合にエラーレスポンスを返して false を返す
+func requireItemAuthorOrStaff(w http.ResponseWriter, item model.Item, userEmail string) bool {
+	if userEmail != "" && item.CreatedBy == userEmail {
+		return true
+	}
+	staff, err := isCourseStaff(item.CourseID, userEmail)
+	if err != nil {
+		logAndSendError(w, "Failed to check course membership", http.StatusInternalServerError, err)
+		return false
+	}
+	if !staff {
+		logAndSendError(w, "Only the author or course instructors can do this", http.StatusForbidden, nil)
+		return false
+	}
+	return true
+}
+
+// courseHasCategory はカテゴリと章がコースに登録されているかを判定する
+func courseHasCategory(courseID, category, chapter string) (bool, error) {
+	var count int
+	err := database.Db.QueryRow(`
+		SELECT (SELECT COUNT(*) FROM categories WHERE courseId = ? AND Name = ?)
+		     * (SELECT COUNT(*) FROM chapters WHERE courseId = ? AND Name = ?)`,
+		courseID, category, courseID, chapter).Scan(&count)
+	return count > 0, err
+}
+
+// canAccessItem は指定したユーザーがアイテム（添付ファイルを含む）を参照できるかを判定する
+func canAccessItem(item model.Item, userEmail string) (bool, error) {
+	if userEmail != "" && item.CreatedBy == userEmail {
+		return true, nil
+	}
+	// レビュアーはレビュー待ちのアイテムを確認できる
+	if item.Status == statusInReview && isReviewer(userEmail) {
+		return true, nil
+	}
+	if !isPublished(item) {
+		return false, nil
+	}
+
+	switch item.Visibility {
+	case visibilityPublic:
+		return true, nil
+	case visibilityCourse:
+		return isCourseMember(item.CourseID, userEmail)
+	case visibilityGroup:
+		return isGroupMember(item.VisibilityGroup, userEmail)
+	}
+	return false, nil
+}
+
+// isPublished は公開中（予約日時を過ぎた予約公開を含む）かどうかを判定する
+// 管理者が非表示にしたアイテムは公開中として扱わない
+func isPublished(item model.Item) bool {
+	if item.Hidden {
+		return false
+	}
+	if item.Status == statusScheduled && item.PublishAt != nil {
+		return !item.PublishAt.After(time.Now().UTC())
+	}
+	return item.Status == statusPublished
+}
+
+// publishedItemsCondition は公開中（予約日時を過ぎた予約公開を含む）のアイテムだけを返すためのWHERE条件
+// 管理者が非表示にしたアイテムは含めない
+// 他のテーブルと結合しても列名が曖昧にならないよう、列は items. を付けて指定する
+func publishedItemsCondition() (string, []interface{}) {
+	return "(items.hidden = FALSE AND (items.status = ? OR (items.status = ? AND items.publishAt <= UTC_TIMESTAMP())))", []interface{}{statusPublished, statusScheduled}
+}
+
+// readableItemsCondition は指定したユーザーが閲覧できるアイテムだけを返すためのWHERE条件（列は items. を付けて指定する）
+// 予約公開はスケジューラの実行を待たず、予約日時を過ぎた時点で公開中として扱う
+func readableItemsCondition(userEmail string) (string, []interface{}) {
+	published, params := publishedItemsCondition()
+
+	// 未ログインの場合は公開範囲が public のものだけ
+	if userEmail == "" {
+		return "(" + published + " AND items.visibility = ?)", append(params, visibilityPublic)
+	}
+
+	visible := "(items.visibility = ?" +
+		" OR (items.visibility = ? AND items.courseId IN (SELECT courseId FROM course_members WHERE userEmail = ?))" +
+		" OR (items.visibility = ? AND items.visibilityGroup IN (SELECT groupId FROM user_group_members WHERE userEmail = ?)))"
+	params = append(params, visibilityPublic, visibilityCourse, userEmail, visibilityGroup, userEmail)
+	return "(items.createdBy = ? OR (" + published + " AND " + visible + "))", append([]interface{}{userEmail}, params...)
+}
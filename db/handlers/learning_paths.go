@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"database/sql"
+	"db/database"
+	"db/model"
+	"errors"
+	"strings"
+	"time"
+)
+
+var errPathItemNotInCourse = errors.New("item does not belong to the course")
+
+// findLearningPath はコース内の学習パスを1件取得する（ユーザーが閲覧できるアイテムのIDを含む）
+func findLearningPath(courseID, id, userEmail string) (model.LearningPath, error) {
+	paths, err := queryLearningPaths(userEmail, "SELECT id, courseId, title, description, createdBy, createdAt, updatedAt FROM learning_paths WHERE courseId = ? AND id = ?", courseID, id)
+	if err != nil {
+		return model.LearningPath{}, err
+	}
+	if len(paths) == 0 {
+		return model.LearningPath{}, sql.ErrNoRows
+	}
+	return paths[0], nil
+}
+
+// queryLearningPaths は学習パスを検索し、ユーザーが閲覧できるアイテムのIDを順番どおりに設定して返す
+// 閲覧できないアイテムのIDは、存在を知られないよう含めない
+func queryLearningPaths(userEmail, query string, params ...interface{}) ([]model.LearningPath, error) {
+	rows, err := database.Db.Query(query, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	paths := []model.LearningPath{}
+	index := map[string]int{}
+	for rows.Next() {
+		var p model.LearningPath
+		var createdAtStr, updatedAtStr string
+		if err := rows.Scan(&p.ID, &p.CourseID, &p.Title, &p.Description, &p.CreatedBy, &createdAtStr, &updatedAtStr); err != nil {
+			return nil, err
+		}
+		if p.CreatedAt, err = time.Parse("2006-01-02 15:04:05", createdAtStr); err != nil {
+			return nil, err
+		}
+		if p.UpdatedAt, err = time.Parse("2006-01-02 15:04:05", updatedAtStr); err != nil {
+			return nil, err
+		}
+		p.ItemIDs = []string{}
+		index[p.ID] = len(paths)
+		paths = append(paths, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return paths, nil
+	}
+
+	ids := make([]interface{}, 0, len(paths))
+	for _, p := range paths {
+		ids = append(ids, p.ID)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(paths)), ", ")
+	readableSQL, readableParams := readableItemsCondition(userEmail)
+	itemRows, err := database.Db.Query("SELECT lp.pathId, lp.itemId FROM learning_path_items lp JOIN items ON items.id = lp.itemId"+
+		" WHERE lp.pathId IN ("+placeholders+") AND "+readableSQL+" ORDER BY lp.pathId, lp.position", append(ids, readableParams...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer itemRows.Close()
+	for itemRows.Next() {
+		var pathID, itemID string
+		if err := itemRows.Scan(&pathID, &itemID); err != nil {
+			return nil, err
+		}
+		p := &paths[index[pathID]]
+		p.ItemIDs = append(p.ItemIDs, itemID)
+	}
+	return paths, itemRows.Err()
+}
+
+// attachPathProgress は学習パスごとにユーザーが完了した、閲覧できるアイテムの数を設定する
+func attachPathProgress(paths []model.LearningPath, userEmail string) error {
+	readableSQL, readableParams := readableItemsCondition(userEmail)
+	for i := range paths {
+		if len(paths[i].ItemIDs) == 0 || userEmail == "" {
+			continue
+		}
+		params := append([]interface{}{userEmail, paths[i].ID}, readableParams...)
+		err := database.Db.QueryRow(`
+			SELECT COUNT(*) FROM learning_path_items lp
+			JOIN item_progress p ON p.itemId = lp.itemId AND p.userEmail = ?
+			JOIN items ON items.id = lp.itemId
+			WHERE lp.pathId = ? AND `+readableSQL, params...).Scan(&paths[i].CompletedCount)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveLearningPathItems は学習パスのアイテムを指定した順番で置き換える
+// 他のコースのアイテムは追加できない
+func saveLearningPathItems(tx *sql.Tx, courseID, pathID string, itemIDs []string) error {
+	if _, err := tx.Exec("DELETE FROM learning_path_items WHERE pathId = ?", pathID); err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	position := 0
+	for _, itemID := range itemIDs {
+		if seen[itemID] {
+			continue
+		}
+		seen[itemID] = true
+
+		var count int
+		if err := tx.QueryRow("SELECT COUNT(*) FROM items WHERE id = ? AND courseId = ?", itemID, courseID).Scan(&count); err != nil {
+			return err
+		}
+		if count == 0 {
+			return errPathItemNotInCourse
+		}
+		if _, err := tx.Exec("INSERT INTO learning_path_items (pathId, position, itemId) VALUES (?, ?, ?)", pathID, position, itemID); err != nil {
+			return err
+		}
+		position++
+	}
+	return nil
+}
@@ -0,0 +1,346 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"db/database"
+	"db/storage"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// 複数のレプリカで同時に実行されないようにするためのロック名
+const linkCheckerLock = "link_checker"
+
+// リンクの確認に使う値
+const (
+	linkCheckTimeout     = 10 * time.Second
+	linkCheckConcurrency = 4
+	maxLinkRedirects     = 5
+	maxLinkErrorLength   = 255
+)
+
+var (
+	linkPattern = regexp.MustCompile("https?://[^\\s<>\"'`()\\[\\]{}]+")
+
+	errPrivateAddress   = errors.New("links to private addresses are not checked")
+	errTooManyRedirects = errors.New("too many redirects")
+)
+
+// HTTPDoer はリンクの確認に使う HTTP クライアント
+// テストではこれを差し替えて、ネットワークに接続せずに実行する
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// LinkChecker はアイテムの本文と添付ファイルのリンク切れを確認する
+type LinkChecker struct {
+	Client      HTTPDoer
+	Concurrency int
+}
+
+// LinkCheckReport はリンクの確認の結果の件数
+type LinkCheckReport struct {
+	Items  int `json:"items"`
+	Links  int `json:"links"`
+	Broken int `json:"broken"`
+}
+
+// linkStatus はリンク1件の確認結果（Status は HTTP のステータスコード、接続できない場合は 0）
+type linkStatus struct {
+	Status int
+	Error  string
+	Broken bool
+}
+
+// NewLinkCheckClient はリンクの確認用の HTTP クライアントを作成する
+// 本文のURLからサーバー内部のネットワークにアクセスされないよう、プライベートアドレスには接続しない
+func NewLinkCheckClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: linkCheckTimeout,
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+				return errPrivateAddress
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout:   linkCheckTimeout,
+		Transport: &http.Transport{DialContext: dialer.DialContext},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxLinkRedirects {
+				return errTooManyRedirects
+			}
+			return nil
+		},
+	}
+}
+
+// DefaultLinkChecker は定期実行と管理者の依頼で使うリンクの確認
+var DefaultLinkChecker = &LinkChecker{Client: NewLinkCheckClient(), Concurrency: linkCheckConcurrency}
+
+// extractLinks はアイテムの本文の http(s) のURLと、添付ファイル（URLまたはストレージのキー）を重複なく返す
+func extractLinks(content, file string) []string {
+	var links []string
+	seen := map[string]bool{}
+	add := func(link string) {
+		if link != "" && !seen[link] {
+			seen[link] = true
+			links = append(links, link)
+		}
+	}
+	for _, link := range linkPattern.FindAllString(content, -1) {
+		// 文末の句読点はURLに含めない
+		add(strings.TrimRight(link, ".,;:!?。、"))
+	}
+	if isStoredFile(file) || linkPattern.MatchString(file) {
+		add(file)
+	}
+	return links
+}
+
+// check はリンクを確認する
+// ストレージのキーはファイルが存在するかを、URLは HEAD（使えない場合は GET）のステータスを確認する
+func (c *LinkChecker) check(ctx context.Context, link string) linkStatus {
+	if isStoredFile(link) {
+		f, err := storage.Store.Open(link)
+		if err != nil {
+			return linkStatus{Status: http.StatusNotFound, Error: err.Error(), Broken: true}
+		}
+		f.Close()
+		return linkStatus{Status: http.StatusOK}
+	}
+
+	status, err := c.request(ctx, http.MethodHead, link)
+	if err == nil && (status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented || status == http.StatusForbidden) {
+		status, err = c.request(ctx, http.MethodGet, link)
+	}
+	if err != nil {
+		// 列の長さは文字数のため、マルチバイト文字の途中で切らないよう文字単位で切り詰める
+		message := []rune(err.Error())
+		if len(message) > maxLinkErrorLength {
+			message = message[:maxLinkErrorLength]
+		}
+		return linkStatus{Error: string(message), Broken: true}
+	}
+	return linkStatus{Status: status, Broken: status >= 400}
+}
+
+func (c *LinkChecker) request(ctx context.Context, method, link string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, link, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", "uttc-hackathon-link-checker")
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// Run はすべてのアイテムのリンクを確認し、結果を保存する
+// MySQL の GET_LOCK で排他し、他のレプリカが実行中の場合は何もしない
+func (c *LinkChecker) Run(ctx context.Context) (LinkCheckReport, error) {
+	var report LinkCheckReport
+
+	// GET_LOCK は接続単位のロックのため、確認が終わるまで同じ接続を保持する
+	conn, err := database.Db.Conn(ctx)
+	if err != nil {
+		return report, err
+	}
+	defer conn.Close()
+
+	var locked sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 0)", linkCheckerLock).Scan(&locked); err != nil {
+		return report, err
+	}
+	if !locked.Valid || locked.Int64 != 1 {
+		return report, nil
+	}
+	defer conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", linkCheckerLock)
+
+	itemLinks, err := loadItemLinks(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	// 同じURLは1回だけ確認する
+	results := map[string]linkStatus{}
+	for _, links := range itemLinks {
+		for _, link := range links {
+			results[link] = linkStatus{}
+		}
+	}
+	c.checkAll(ctx, results)
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+
+	for itemID, links := range itemLinks {
+		if err := saveLinkStatuses(ctx, itemID, links, results); err != nil {
+			return report, err
+		}
+		report.Items++
+		report.Links += len(links)
+		for _, link := range links {
+			if results[link].Broken {
+				report.Broken++
+			}
+		}
+	}
+	return report, nil
+}
+
+// checkAll は Concurrency 件ずつ並行してリンクを確認し、results に結果を設定する
+func (c *LinkChecker) checkAll(ctx context.Context, results map[string]linkStatus) {
+	concurrency := c.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	// ワーカーが results に書き込む間に map を走査しないよう、先にURLを取り出しておく
+	links := make([]string, 0, len(results))
+	for link := range results {
+		links = append(links, link)
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for _, link := range links {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(link string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			status := c.check(ctx, link)
+			mu.Lock()
+			results[link] = status
+			mu.Unlock()
+		}(link)
+	}
+	wg.Wait()
+}
+
+// loadItemLinks はアイテムごとのリンクを読み込む（リンクのないアイテムも、以前の結果を消すため含める）
+func loadItemLinks(ctx context.Context) (map[string][]string, error) {
+	rows, err := database.Db.QueryContext(ctx, "SELECT id, content, file FROM items")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	itemLinks := map[string][]string{}
+	for rows.Next() {
+		var id, content, file string
+		if err := rows.Scan(&id, &content, &file); err != nil {
+			return nil, err
+		}
+		itemLinks[id] = extractLinks(content, file)
+	}
+	return itemLinks, rows.Err()
+}
+
+// saveLinkStatuses はアイテムのリンクの確認結果を置き換える
+func saveLinkStatuses(ctx context.Context, itemID string, links []string, results map[string]linkStatus) error {
+	tx, err := database.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM link_checks WHERE itemId = ?", itemID); err != nil {
+		return err
+	}
+	for _, link := range links {
+		s := results[link]
+		hash := sha256.Sum256([]byte(link))
+		_, err := tx.ExecContext(ctx, "INSERT INTO link_checks (itemId, urlHash, url, status, error, broken, checkedAt) VALUES (?, ?, ?, ?, ?, ?, UTC_TIMESTAMP())",
+			itemID, hex.EncodeToString(hash[:]), link, s.Status, s.Error, s.Broken)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// BrokenLink はリンク切れのURLと確認結果
+type BrokenLink struct {
+	URL       string    `json:"url"`
+	Status    int       `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// BrokenLinkItem はリンク切れのあるアイテム
+type BrokenLinkItem struct {
+	ItemID string       `json:"itemId"`
+	Title  string       `json:"title"`
+	Links  []BrokenLink `json:"links"`
+}
+
+// AuthorBrokenLinks は作成者ごとのリンク切れのあるアイテム
+type AuthorBrokenLinks struct {
+	Author     string           `json:"author"`
+	AuthorName string           `json:"authorName"`
+	Items      []BrokenLinkItem `json:"items"`
+}
+
+// brokenLinksByAuthor はコース内のリンク切れを作成者・アイテムごとにまとめて返す
+// author を指定した場合はその作成者のアイテムのみ
+func brokenLinksByAuthor(courseID, author string) ([]AuthorBrokenLinks, error) {
+	query := `
+		SELECT items.createdBy, items.createdByName, items.id, items.title, l.url, l.status, l.error, l.checkedAt
+		FROM link_checks l JOIN items ON items.id = l.itemId
+		WHERE items.courseId = ? AND l.broken = TRUE`
+	params := []interface{}{courseID}
+	if author != "" {
+		query += " AND items.createdBy = ?"
+		params = append(params, author)
+	}
+	query += " ORDER BY items.createdBy, items.createdAt, items.id, l.url"
+
+	rows, err := database.Db.Query(query, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	report := []AuthorBrokenLinks{}
+	for rows.Next() {
+		var createdBy, createdByName, itemID, title, checkedAtStr string
+		var link BrokenLink
+		if err := rows.Scan(&createdBy, &createdByName, &itemID, &title, &link.URL, &link.Status, &link.Error, &checkedAtStr); err != nil {
+			return nil, err
+		}
+		if link.CheckedAt, err = time.Parse("2006-01-02 15:04:05", checkedAtStr); err != nil {
+			return nil, err
+		}
+
+		if len(report) == 0 || report[len(report)-1].Author != createdBy {
+			report = append(report, AuthorBrokenLinks{Author: createdBy, AuthorName: createdByName, Items: []BrokenLinkItem{}})
+		}
+		a := &report[len(report)-1]
+		if len(a.Items) == 0 || a.Items[len(a.Items)-1].ItemID != itemID {
+			a.Items = append(a.Items, BrokenLinkItem{ItemID: itemID, Title: title, Links: []BrokenLink{}})
+		}
+		item := &a.Items[len(a.Items)-1]
+		item.Links = append(item.Links, link)
+	}
+	return report, rows.Err()
+}
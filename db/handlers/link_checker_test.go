@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+)
+
+// fakeDoer はURLとメソッドごとに決めたステータスを返す HTTPDoer
+type fakeDoer struct {
+	mu       sync.Mutex
+	statuses map[string]int // "METHOD URL" -> ステータス
+	errs     map[string]error
+	requests []string
+}
+
+func (d *fakeDoer) Do(req *http.Request) (*http.Response, error) {
+	key := req.Method + " " + req.URL.String()
+	d.mu.Lock()
+	d.requests = append(d.requests, key)
+	d.mu.Unlock()
+	if err := d.errs[req.URL.String()]; err != nil {
+		return nil, err
+	}
+	status, ok := d.statuses[key]
+	if !ok {
+		status = http.StatusOK
+	}
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(""))}, nil
+}
+
+func TestExtractLinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		file    string
+		want    []string
+	}{
+		{
+			name:    "trailing punctuation",
+			content: "See https://example.com/a. Also https://example.com/b, and https://example.com/c。",
+			want:    []string{"https://example.com/a", "https://example.com/b", "https://example.com/c"},
+		},
+		{
+			name:    "markdown link and duplicates",
+			content: "[doc](https://example.com/doc) https://example.com/doc",
+			want:    []string{"https://example.com/doc"},
+		},
+		{
+			name: "stored file key",
+			file: "files/abc.pdf",
+			want: []string{"files/abc.pdf"},
+		},
+		{
+			name: "file url",
+			file: "https://example.com/slides.pdf",
+			want: []string{"https://example.com/slides.pdf"},
+		},
+		{
+			name: "other file values are ignored",
+			file: "slides.pdf",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractLinks(tt.content, tt.file)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractLinks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinkCheckerCheck(t *testing.T) {
+	const link = "https://example.com/page"
+	tests := []struct {
+		name       string
+		statuses   map[string]int
+		err        error
+		wantStatus int
+		wantBroken bool
+		wantGet    bool
+	}{
+		{name: "ok", wantStatus: http.StatusOK},
+		{name: "not found", statuses: map[string]int{"HEAD " + link: http.StatusNotFound}, wantStatus: http.StatusNotFound, wantBroken: true},
+		{name: "server error", statuses: map[string]int{"HEAD " + link: http.StatusBadGateway}, wantStatus: http.StatusBadGateway, wantBroken: true},
+		{name: "head not allowed", statuses: map[string]int{"HEAD " + link: http.StatusMethodNotAllowed}, wantStatus: http.StatusOK, wantGet: true},
+		{name: "head not implemented", statuses: map[string]int{"HEAD " + link: http.StatusNotImplemented}, wantStatus: http.StatusOK, wantGet: true},
+		{name: "head forbidden", statuses: map[string]int{"HEAD " + link: http.StatusForbidden, "GET " + link: http.StatusForbidden}, wantStatus: http.StatusForbidden, wantBroken: true, wantGet: true},
+		{name: "connection error", err: errors.New("connection refused"), wantBroken: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doer := &fakeDoer{statuses: tt.statuses, errs: map[string]error{link: tt.err}}
+			c := &LinkChecker{Client: doer}
+			got := c.check(context.Background(), link)
+			if got.Status != tt.wantStatus || got.Broken != tt.wantBroken {
+				t.Errorf("check() = %+v, want status %d broken %v", got, tt.wantStatus, tt.wantBroken)
+			}
+			if tt.err != nil && got.Error == "" {
+				t.Errorf("check() error is empty")
+			}
+			gotGet := false
+			for _, r := range doer.requests {
+				if strings.HasPrefix(r, "GET ") {
+					gotGet = true
+				}
+			}
+			if gotGet != tt.wantGet {
+				t.Errorf("GET fallback = %v, want %v (requests %v)", gotGet, tt.wantGet, doer.requests)
+			}
+		})
+	}
+}
+
+func TestLinkCheckerCheckAll(t *testing.T) {
+	doer := &fakeDoer{statuses: map[string]int{}}
+	results := map[string]linkStatus{}
+	for i := 0; i < 50; i++ {
+		link := "https://example.com/" + strings.Repeat("x", i)
+		results[link] = linkStatus{}
+		if i%2 == 0 {
+			doer.statuses["HEAD "+link] = http.StatusNotFound
+		}
+	}
+
+	c := &LinkChecker{Client: doer, Concurrency: 8}
+	c.checkAll(context.Background(), results)
+
+	broken := 0
+	for link, s := range results {
+		if s.Status == 0 {
+			t.Errorf("%s was not checked", link)
+		}
+		if s.Broken {
+			broken++
+		}
+	}
+	if broken != 25 {
+		t.Errorf("broken = %d, want 25", broken)
+	}
+}
+
+func TestLinkCheckerCheckTruncatesError(t *testing.T) {
+	const link = "https://example.com/page"
+	message := "x" + strings.Repeat("接続できません", 100)
+	doer := &fakeDoer{errs: map[string]error{link: errors.New(message)}}
+	c := &LinkChecker{Client: doer}
+	got := c.check(context.Background(), link)
+	if !utf8.ValidString(got.Error) {
+		t.Errorf("check() error is not valid UTF-8: %q", got.Error)
+	}
+	if n := utf8.RuneCountInString(got.Error); n != maxLinkErrorLength {
+		t.Errorf("check() error has %d characters, want %d", n, maxLinkErrorLength)
+	}
+}
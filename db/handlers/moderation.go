@@ -0,0 +1,325 @@
+package handlers
+
+import (
+	"database/sql"
+	"db/database"
+	"db/model"
+	"encoding/json"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// 通報の理由
+const (
+	reportReasonInappropriate = "inappropriate" // 不適切な内容
+	reportReasonIncorrect     = "incorrect"     // 内容の誤り
+	reportReasonSpam          = "spam"          // スパム・宣伝
+	reportReasonOther         = "other"         // その他（comment に詳細を書く）
+)
+
+// isValidReportReason は通報の理由の値が正しいかどうかを判定する
+func isValidReportReason(reason string) bool {
+	switch reason {
+	case reportReasonInappropriate, reportReasonIncorrect, reportReasonSpam, reportReasonOther:
+		return true
+	}
+	return false
+}
+
+// 通報の状態
+const (
+	reportStatusOpen      = "open"      // 未対応
+	reportStatusResolved  = "resolved"  // アイテムを非表示にして対応済み
+	reportStatusDismissed = "dismissed" // 問題なしとして却下
+)
+
+// 管理者の対応
+const (
+	moderationActionHide    = "hide"    // アイテムを非表示にし、未対応の通報を対応済みにする
+	moderationActionRestore = "restore" // 非表示にしたアイテムを再表示する
+	moderationActionDismiss = "dismiss" // 非表示にせず、未対応の通報を却下する
+)
+
+// ModerationQueueItem は未対応の通報があるアイテムと、その通報の一覧
+type ModerationQueueItem struct {
+	Item    model.Item         `json:"item"`
+	Reports []model.ItemReport `json:"reports"`
+}
+
+// bannedWords は環境変数 BANNED_WORDS（カンマ区切り）に設定された禁止語を返す
+// 再起動せずに変更できるよう、呼び出すたびに読み込む
+func bannedWords() []string {
+	var words []string
+	for _, word := range strings.Split(os.Getenv("BANNED_WORDS"), ",") {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// bannedWordPattern は禁止語を単語単位で探す正規表現を返す
+// 英数字で始まる（終わる）禁止語は前（後ろ）に英数字が続く場合を除外し、"ass" が "class" に一致しないようにする
+// 分かち書きされない日本語は単語の区切りが分からないため、そのまま部分一致で探す
+func bannedWordPattern(word string) *regexp.Regexp {
+	runes := []rune(word)
+	pattern := regexp.QuoteMeta(word)
+	if isAlphanumeric(runes[0]) {
+		pattern = `(?:^|[^\p{L}\p{N}])` + pattern
+	}
+	if isAlphanumeric(runes[len(runes)-1]) {
+		pattern += `(?:$|[^\p{L}\p{N}])`
+	}
+	return regexp.MustCompile(pattern)
+}
+
+// isAlphanumeric は文字が日本語以外の文字・数字かどうかを判定する
+func isAlphanumeric(r rune) bool {
+	if unicode.In(r, unicode.Han, unicode.Katakana, unicode.Hiragana) {
+		return false
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// findBannedWords はタイトル・本文・タグに含まれる禁止語を返す（大文字・小文字は区別しない）
+func findBannedWords(title, content string, tags []string) []string {
+	text := strings.ToLower(title + "\n" + content + "\n" + strings.Join(tags, "\n"))
+	var found []string
+	for _, word := range bannedWords() {
+		if bannedWordPattern(word).MatchString(text) {
+			found = append(found, word)
+		}
+	}
+	return found
+}
+
+// rejectBannedWords は禁止語が含まれる場合に 400 と含まれていた禁止語を返して false を返す
+func rejectBannedWords(w http.ResponseWriter, title, content string, tags []string) bool {
+	words := findBannedWords(title, content, tags)
+	if len(words) == 0 {
+		return true
+	}
+	w.Header().Set("Access-Control-Allow-Origin", "https://uttc-hackathon-fe.vercel.app") // フロントエンドのオリジン
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	responseData := map[string]interface{}{"message": "Content contains banned words", "words": words}
+	json.NewEncoder(w).Encode(responseData)
+	return false
+}
+
+// createReport はアイテムへの通報を保存する
+// 同じユーザーが同じアイテムに未対応の通報をすでにしている場合は false を返す
+func createReport(itemID, reporter, reason, comment string) (model.ItemReport, bool, error) {
+	report := model.ItemReport{
+		ItemID:   itemID,
+		Reporter: reporter,
+		Reason:   reason,
+		Comment:  comment,
+		Status:   reportStatusOpen,
+	}
+
+	tx, err := database.Db.Begin()
+	if err != nil {
+		return report, false, err
+	}
+	defer tx.Rollback()
+
+	// 同じアイテムへの通報を直列化し、未対応の通報が重複しないようにする
+	if _, err := tx.Exec("SELECT id FROM items WHERE id = ? FOR UPDATE", itemID); err != nil {
+		return report, false, err
+	}
+	var count int
+	err = tx.QueryRow("SELECT COUNT(*) FROM item_reports WHERE itemId = ? AND reporter = ? AND status = ?",
+		itemID, reporter, reportStatusOpen).Scan(&count)
+	if err != nil {
+		return report, false, err
+	}
+	if count > 0 {
+		return report, false, nil
+	}
+
+	if report.ID, err = generateULID(); err != nil {
+		return report, false, err
+	}
+	_, err = tx.Exec("INSERT INTO item_reports (id, itemId, reporter, reason, comment, status) VALUES (?, ?, ?, ?, ?, ?)",
+		report.ID, itemID, reporter, reason, comment, reportStatusOpen)
+	if err != nil {
+		return report, false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return report, false, err
+	}
+	report.CreatedAt = time.Now()
+	return report, true, nil
+}
+
+// scanReport は item_reports の行を model.ItemReport に変換する
+func scanReport(s scanner) (model.ItemReport, error) {
+	var report model.ItemReport
+	var createdAtStr string
+	var resolvedBy, resolvedAtStr sql.NullString
+	err := s.Scan(&report.ID, &report.ItemID, &report.Reporter, &report.Reason, &report.Comment, &report.Status,
+		&createdAtStr, &resolvedBy, &resolvedAtStr)
+	if err != nil {
+		return report, err
+	}
+	if report.CreatedAt, err = time.Parse("2006-01-02 15:04:05", createdAtStr); err != nil {
+		return report, err
+	}
+	report.ResolvedBy = resolvedBy.String
+	if resolvedAtStr.Valid {
+		resolvedAt, err := time.Parse("2006-01-02 15:04:05", resolvedAtStr.String)
+		if err != nil {
+			return report, err
+		}
+		report.ResolvedAt = &resolvedAt
+	}
+	return report, nil
+}
+
+// moderationQueue は未対応の通報があるアイテムを、通報の多い順（同数の場合は古い通報がある順）に返す
+func moderationQueue(courseID string) ([]ModerationQueueItem, error) {
+	query := "SELECT id, itemId, reporter, reason, comment, status, createdAt, resolvedBy, resolvedAt FROM item_reports WHERE status = ?"
+	params := []interface{}{reportStatusOpen}
+	if courseID != "" {
+		query += " AND itemId IN (SELECT id FROM items WHERE courseId = ?)"
+		params = append(params, courseID)
+	}
+	rows, err := database.Db.Query(query+" ORDER BY createdAt, id", params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var order []string
+	reports := map[string][]model.ItemReport{}
+	for rows.Next() {
+		report, err := scanReport(rows)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := reports[report.ItemID]; !ok {
+			order = append(order, report.ItemID)
+		}
+		reports[report.ItemID] = append(reports[report.ItemID], report)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	queue := []ModerationQueueItem{}
+	for _, itemID := range order {
+		item, err := findItem(itemID)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		queue = append(queue, ModerationQueueItem{Item: item, Reports: reports[itemID]})
+	}
+	// 通報の古い順に並んでいるため、安定ソートで件数の多い順にする
+	sort.SliceStable(queue, func(i, j int) bool { return len(queue[i].Reports) > len(queue[j].Reports) })
+	return queue, nil
+}
+
+// moderateItem は管理者の対応をアイテムと通報に反映し、作成者への通知と監査ログを残す
+// 対応した通報の件数を返す
+func moderateItem(item model.Item, moderator, action, note string) (int64, error) {
+	tx, err := database.Db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("SELECT id FROM items WHERE id = ? FOR UPDATE", item.ID); err != nil {
+		return 0, err
+	}
+
+	var message string
+	reportStatus := ""
+	switch action {
+	case moderationActionHide:
+		// 非表示はアイテムの更新ではないため更新日時は変えない
+		if _, err := tx.Exec("UPDATE items SET hidden = TRUE, updatedAt = updatedAt WHERE id = ?", item.ID); err != nil {
+			return 0, err
+		}
+		reportStatus = reportStatusResolved
+		message = "「" + item.Title + "」は管理者により非表示になりました"
+	case moderationActionRestore:
+		if _, err := tx.Exec("UPDATE items SET hidden = FALSE, updatedAt = updatedAt WHERE id = ?", item.ID); err != nil {
+			return 0, err
+		}
+		message = "「" + item.Title + "」は管理者により再表示されました"
+	case moderationActionDismiss:
+		reportStatus = reportStatusDismissed
+	}
+	if note != "" && message != "" {
+		message += "（" + note + "）"
+	}
+
+	var handled int64
+	if reportStatus != "" {
+		result, err := tx.Exec("UPDATE item_reports SET status = ?, resolvedBy = ?, resolvedAt = NOW() WHERE itemId = ? AND status = ?",
+			reportStatus, moderator, item.ID, reportStatusOpen)
+		if err != nil {
+			return 0, err
+		}
+		if handled, err = result.RowsAffected(); err != nil {
+			return 0, err
+		}
+	}
+
+	// 通報の却下は作成者に関係しないため通知しない
+	if message != "" {
+		if err := notify(tx, item.CreatedBy, item.ID, message); err != nil {
+			return 0, err
+		}
+	}
+
+	// 監査ログはアイテムを削除しても残す
+	id, err := generateULID()
+	if err != nil {
+		return 0, err
+	}
+	_, err = tx.Exec("INSERT INTO moderation_log (id, itemId, courseId, moderator, action, note, reports) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		id, item.ID, item.CourseID, moderator, action, note, handled)
+	if err != nil {
+		return 0, err
+	}
+	return handled, tx.Commit()
+}
+
+// moderationLog は管理者の対応の履歴を新しい順に返す
+func moderationLog(itemID string, limit int) ([]model.ModerationLogEntry, error) {
+	query := "SELECT id, itemId, courseId, moderator, action, note, reports, createdAt FROM moderation_log"
+	var params []interface{}
+	if itemID != "" {
+		query += " WHERE itemId = ?"
+		params = append(params, itemID)
+	}
+	rows, err := database.Db.Query(query+" ORDER BY createdAt DESC, id DESC LIMIT ?", append(params, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []model.ModerationLogEntry{}
+	for rows.Next() {
+		var entry model.ModerationLogEntry
+		var createdAtStr string
+		if err := rows.Scan(&entry.ID, &entry.ItemID, &entry.CourseID, &entry.Moderator, &entry.Action, &entry.Note, &entry.Reports, &createdAtStr); err != nil {
+			return nil, err
+		}
+		if entry.CreatedAt, err = time.Parse("2006-01-02 15:04:05", createdAtStr); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
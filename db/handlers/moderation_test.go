@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindBannedWords(t *testing.T) {
+	t.Setenv("BANNED_WORDS", "ass, Spam Bot ,死ね,c++")
+	tests := []struct {
+		name    string
+		title   string
+		content string
+		tags    []string
+		want    []string
+	}{
+		{name: "inside a word", title: "Class notes", content: "You pass if you assess the bass."},
+		{name: "whole word", content: "what an ass.", want: []string{"ass"}},
+		{name: "case insensitive", title: "ASS", want: []string{"ass"}},
+		{name: "start and end of text", content: "ass", want: []string{"ass"}},
+		{name: "non-ascii neighbour", content: "éass and assé"},
+		{name: "phrase", content: "a spam bot!", want: []string{"spam bot"}},
+		{name: "phrase inside words", content: "antispam bots"},
+		{name: "japanese substring", content: "お前は死ねと言った", want: []string{"死ね"}},
+		{name: "symbol at the end", content: "I like c++ a lot", want: []string{"c++"}},
+		{name: "tags", tags: []string{"math", "ass"}, want: []string{"ass"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findBannedWords(tt.title, tt.content, tt.tags)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findBannedWords() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"database/sql"
+	"db/database"
+	"time"
+)
+
+// Notification はユーザーへの通知
+type Notification struct {
+	ID        string    `json:"id"`
+	ItemID    string    `json:"itemId"`
+	Message   string    `json:"message"`
+	IsRead    bool      `json:"isRead"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// notify は通知を保存する
+func notify(tx *sql.Tx, userEmail, itemID, message string) error {
+	id, err := generateULID()
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("INSERT INTO notifications (id, userEmail, itemId, message) VALUES (?, ?, ?, ?)",
+		id, userEmail, itemID, message)
+	return err
+}
+
+// listNotifications は新しい順に通知を取得する
+func listNotifications(userEmail string, unreadOnly bool) ([]Notification, error) {
+	query := "SELECT id, itemId, message, isRead, createdAt FROM notifications WHERE userEmail = ?"
+	if unreadOnly {
+		query += " AND isRead = FALSE"
+	}
+	query += " ORDER BY createdAt DESC, id DESC LIMIT 100"
+
+	rows, err := database.Db.Query(query, userEmail)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []Notification{}
+	for rows.Next() {
+		var n Notification
+		var createdAtStr string
+		if err := rows.Scan(&n.ID, &n.ItemID, &n.Message, &n.IsRead, &createdAtStr); err != nil {
+			return nil, err
+		}
+		if n.CreatedAt, err = time.Parse("2006-01-02 15:04:05", createdAtStr); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, rows.Err()
+}
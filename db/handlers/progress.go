@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"db/database"
+)
+
+// ProgressStat は完了したアイテム数と対象のアイテム数
+type ProgressStat struct {
+	Name       string  `json:"name"`
+	Total      int     `json:"total"`
+	Completed  int     `json:"completed"`
+	Percentage float64 `json:"percentage"`
+}
+
+// CategoryProgress はカテゴリの進捗と、その中の章ごとの進捗
+type CategoryProgress struct {
+	ProgressStat
+	Chapters []ProgressStat `json:"chapters"`
+}
+
+// CourseProgress はコース全体・カテゴリ別・章別の進捗
+type CourseProgress struct {
+	Overall    ProgressStat       `json:"overall"`
+	Categories []CategoryProgress `json:"categories"`
+	Chapters   []ProgressStat     `json:"chapters"`
+	Quizzes    QuizSummary        `json:"quizzes"`
+}
+
+// QuizSummary は小テストのあるアイテムの数と、ユーザーの解答状況
+// AverageScore は解答した小テストごとの最高得点率の平均
+type QuizSummary struct {
+	Total        int     `json:"total"`
+	Attempted    int     `json:"attempted"`
+	Passed       int     `json:"passed"`
+	AverageScore float64 `json:"averageScore"`
+}
+
+func (s *ProgressStat) add(total, completed int) {
+	s.Total += total
+	s.Completed += completed
+	if s.Total > 0 {
+		s.Percentage = float64(s.Completed) * 100 / float64(s.Total)
+	}
+}
+
+// learnableItemsQuery はユーザーが学習の対象にできる（閲覧でき、公開中の）コース内のアイテムを返すサブクエリ
+func learnableItemsQuery(courseID, userEmail string) (string, []interface{}) {
+	readableSQL, readableParams := readableItemsCondition(userEmail)
+	publishedSQL, publishedParams := publishedItemsCondition()
+	params := append([]interface{}{courseID}, readableParams...)
+	return "SELECT id, category, chapter FROM items WHERE courseId = ? AND " + readableSQL + " AND " + publishedSQL,
+		append(params, publishedParams...)
+}
+
+// courseProgress はカテゴリ・章のテーブルをもとに、ユーザーの進捗を集計する
+// アイテムのないカテゴリ・章も 0 件として含める
+func courseProgress(courseID, userEmail string) (CourseProgress, error) {
+	progress := CourseProgress{Overall: ProgressStat{Name: "overall"}, Categories: []CategoryProgress{}, Chapters: []ProgressStat{}}
+	learnable, learnableParams := learnableItemsQuery(courseID, userEmail)
+
+	// プレースホルダはサブクエリ、p.userEmail、c.courseId の順
+	params := append(learnableParams, userEmail, courseID)
+
+	// カテゴリ × 章ごとの件数
+	rows, err := database.Db.Query(`
+		SELECT c.Name, i.chapter, COUNT(i.id), COUNT(p.itemId)
+		FROM categories c
+		LEFT JOIN (`+learnable+`) i ON i.category = c.Name
+		LEFT JOIN item_progress p ON p.itemId = i.id AND p.userEmail = ?
+		WHERE c.courseId = ?
+		GROUP BY c.Name, i.chapter
+		ORDER BY c.Name, i.chapter`, params...)
+	if err != nil {
+		return progress, err
+	}
+	defer rows.Close()
+
+	index := map[string]int{}
+	for rows.Next() {
+		var category string
+		var chapter *string
+		var total, completed int
+		if err := rows.Scan(&category, &chapter, &total, &completed); err != nil {
+			return progress, err
+		}
+		i, ok := index[category]
+		if !ok {
+			i = len(progress.Categories)
+			index[category] = i
+			progress.Categories = append(progress.Categories, CategoryProgress{ProgressStat: ProgressStat{Name: category}, Chapters: []ProgressStat{}})
+		}
+		c := &progress.Categories[i]
+		c.add(total, completed)
+		if chapter != nil {
+			stat := ProgressStat{Name: *chapter}
+			stat.add(total, completed)
+			c.Chapters = append(c.Chapters, stat)
+		}
+		progress.Overall.add(total, completed)
+	}
+	if err := rows.Err(); err != nil {
+		return progress, err
+	}
+
+	// 章ごとの件数（カテゴリをまたいで集計）
+	chapterRows, err := database.Db.Query(`
+		SELECT c.Name, COUNT(i.id), COUNT(p.itemId)
+		FROM chapters c
+		LEFT JOIN (`+learnable+`) i ON i.chapter = c.Name
+		LEFT JOIN item_progress p ON p.itemId = i.id AND p.userEmail = ?
+		WHERE c.courseId = ?
+		GROUP BY c.Name
+		ORDER BY c.Name`, params...)
+	if err != nil {
+		return progress, err
+	}
+	defer chapterRows.Close()
+
+	for chapterRows.Next() {
+		var stat ProgressStat
+		var total, completed int
+		if err := chapterRows.Scan(&stat.Name, &total, &completed); err != nil {
+			return progress, err
+		}
+		stat.add(total, completed)
+		progress.Chapters = append(progress.Chapters, stat)
+	}
+	if err := chapterRows.Err(); err != nil {
+		return progress, err
+	}
+
+	// 小テストの解答状況
+	q := &progress.Quizzes
+	err = database.Db.QueryRow(`
+		SELECT COUNT(*), COUNT(a.itemId), COALESCE(SUM(a.passed), 0), COALESCE(AVG(a.best), 0)
+		FROM (`+learnable+`) i
+		JOIN (SELECT DISTINCT itemId FROM quiz_questions) qq ON qq.itemId = i.id
+		LEFT JOIN (
+			SELECT itemId, MAX(IF(maxScore > 0, score * 100 / maxScore, 100)) AS best, MAX(passed) AS passed
+			FROM quiz_attempts WHERE userEmail = ? GROUP BY itemId
+		) a ON a.itemId = i.id`, append(learnableParams, userEmail)...).Scan(&q.Total, &q.Attempted, &q.Passed, &q.AverageScore)
+	return progress, err
+}
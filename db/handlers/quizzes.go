@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"database/sql"
+	"db/database"
+	"db/model"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// 小テストの問題の種類
+const (
+	questionChoice = "choice"
+	questionShort  = "short"
+)
+
+// quizPassPercentage は合格とする得点率（合格するとアイテムを学習完了として記録する）
+const quizPassPercentage = 80
+
+var errInvalidQuestion = errors.New("invalid quiz question")
+
+// validateQuestion は問題の種類ごとに必要な項目がそろっているかを検証する
+func validateQuestion(q model.QuizQuestion) error {
+	if strings.TrimSpace(q.Prompt) == "" || q.Points < 0 {
+		return errInvalidQuestion
+	}
+	switch q.Kind {
+	case questionChoice:
+		if len(q.Choices) < 2 || q.CorrectChoice == nil || *q.CorrectChoice < 0 || *q.CorrectChoice >= len(q.Choices) {
+			return errInvalidQuestion
+		}
+	case questionShort:
+		if len(q.AcceptedAnswers) == 0 {
+			return errInvalidQuestion
+		}
+	default:
+		return errInvalidQuestion
+	}
+	return nil
+}
+
+// listQuizQuestions はアイテムの問題を順番どおりに返す
+// withAnswers が false の場合は正解を含めない
+func listQuizQuestions(itemID string, withAnswers bool) ([]model.QuizQuestion, error) {
+	rows, err := database.Db.Query("SELECT id, itemId, kind, prompt, choices, points, correctChoice, acceptedAnswers FROM quiz_questions WHERE itemId = ? ORDER BY position", itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	questions := []model.QuizQuestion{}
+	for rows.Next() {
+		var q model.QuizQuestion
+		var choicesJSON, answersJSON string
+		var correct sql.NullInt64
+		if err := rows.Scan(&q.ID, &q.ItemID, &q.Kind, &q.Prompt, &choicesJSON, &q.Points, &correct, &answersJSON); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(choicesJSON), &q.Choices); err != nil {
+			return nil, err
+		}
+		if withAnswers {
+			if correct.Valid {
+				c := int(correct.Int64)
+				q.CorrectChoice = &c
+			}
+			if err := json.Unmarshal([]byte(answersJSON), &q.AcceptedAnswers); err != nil {
+				return nil, err
+			}
+		}
+		questions = append(questions, q)
+	}
+	return questions, rows.Err()
+}
+
+// saveQuizQuestions はアイテムの問題を指定した順番で置き換える
+// 問題のIDはこのアイテムの既存の問題のものだけを引き継ぎ、それ以外は新しく作成する
+func saveQuizQuestions(tx *sql.Tx, itemID string, questions []model.QuizQuestion) error {
+	existing := map[string]bool{}
+	rows, err := tx.Query("SELECT id FROM quiz_questions WHERE itemId = ?", itemID)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		existing[id] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM quiz_questions WHERE itemId = ?", itemID); err != nil {
+		return err
+	}
+	for i, q := range questions {
+		if err := validateQuestion(q); err != nil {
+			return err
+		}
+		// 同じIDが2回指定された場合も、2問目以降は新しいIDにする
+		if !existing[q.ID] {
+			q.ID = ""
+		}
+		delete(existing, q.ID)
+		if q.ID == "" {
+			id, err := generateULID()
+			if err != nil {
+				return err
+			}
+			q.ID = id
+		}
+		if q.Points == 0 {
+			q.Points = 1
+		}
+		if q.Kind == questionShort {
+			q.Choices, q.CorrectChoice = nil, nil
+		} else {
+			q.AcceptedAnswers = nil
+		}
+		choicesJSON, err := json.Marshal(append([]string{}, q.Choices...))
+		if err != nil {
+			return err
+		}
+		answersJSON, err := json.Marshal(append([]string{}, q.AcceptedAnswers...))
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec("INSERT INTO quiz_questions (id, itemId, position, kind, prompt, choices, points, correctChoice, acceptedAnswers) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			q.ID, itemID, i, q.Kind, q.Prompt, string(choicesJSON), q.Points, q.CorrectChoice, string(answersJSON))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// QuizAnswer はユーザーが提出した問題ごとの解答
+type QuizAnswer struct {
+	QuestionID string `json:"questionId"`
+	Choice     *int   `json:"choice"`
+	Text       string `json:"text"`
+}
+
+// gradeQuiz は提出された解答を採点する（解答していない問題は不正解）
+func gradeQuiz(questions []model.QuizQuestion, answers []QuizAnswer) model.QuizAttempt {
+	byQuestion := make(map[string]QuizAnswer, len(answers))
+	for _, a := range answers {
+		byQuestion[a.QuestionID] = a
+	}
+
+	attempt := model.QuizAttempt{Results: []model.QuizResult{}}
+	for _, q := range questions {
+		a, answered := byQuestion[q.ID]
+		correct := false
+		if answered {
+			switch q.Kind {
+			case questionChoice:
+				correct = a.Choice != nil && q.CorrectChoice != nil && *a.Choice == *q.CorrectChoice
+			case questionShort:
+				for _, accepted := range q.AcceptedAnswers {
+					if normalizeAnswer(accepted) == normalizeAnswer(a.Text) {
+						correct = true
+					}
+				}
+			}
+		}
+
+		result := model.QuizResult{QuestionID: q.ID, Correct: correct}
+		if correct {
+			result.Points = q.Points
+		}
+		attempt.Score += result.Points
+		attempt.MaxScore += q.Points
+		attempt.Results = append(attempt.Results, result)
+	}
+	attempt.Percentage = scorePercentage(attempt.Score, attempt.MaxScore)
+	attempt.Passed = attempt.Percentage >= quizPassPercentage
+	return attempt
+}
+
+// normalizeAnswer は記述式の解答を比較するため、前後・連続する空白と大文字小文字の違いをなくす
+func normalizeAnswer(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+func scorePercentage(score, maxScore int) float64 {
+	if maxScore == 0 {
+		return 100
+	}
+	return float64(score) * 100 / float64(maxScore)
+}
+
+// saveQuizAttempt は採点結果を保存し、合格した場合はアイテムを学習完了にする
+func saveQuizAttempt(attempt *model.QuizAttempt) error {
+	id, err := generateULID()
+	if err != nil {
+		return err
+	}
+	resultsJSON, err := json.Marshal(attempt.Results)
+	if err != nil {
+		return err
+	}
+
+	tx, err := database.Db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("INSERT INTO quiz_attempts (id, itemId, userEmail, score, maxScore, passed, results) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		id, attempt.ItemID, attempt.UserEmail, attempt.Score, attempt.MaxScore, attempt.Passed, string(resultsJSON))
+	if err != nil {
+		return err
+	}
+	if attempt.Passed {
+		if _, err := tx.Exec("INSERT IGNORE INTO item_progress (userEmail, itemId) VALUES (?, ?)", attempt.UserEmail, attempt.ItemID); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	attempt.ID = id
+	attempt.CreatedAt = time.Now().UTC()
+	return nil
+}
+
+// listQuizAttempts はユーザーの小テストの解答履歴を新しい順に返す
+func listQuizAttempts(itemID, userEmail string) ([]model.QuizAttempt, error) {
+	rows, err := database.Db.Query("SELECT id, itemId, userEmail, score, maxScore, passed, createdAt FROM quiz_attempts WHERE itemId = ? AND userEmail = ? ORDER BY createdAt DESC, id DESC LIMIT 100", itemID, userEmail)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attempts := []model.QuizAttempt{}
+	for rows.Next() {
+		var a model.QuizAttempt
+		var createdAtStr string
+		if err := rows.Scan(&a.ID, &a.ItemID, &a.UserEmail, &a.Score, &a.MaxScore, &a.Passed, &createdAtStr); err != nil {
+			return nil, err
+		}
+		if a.CreatedAt, err = time.Parse("2006-01-02 15:04:05", createdAtStr); err != nil {
+			return nil, err
+		}
+		a.Percentage = scorePercentage(a.Score, a.MaxScore)
+		attempts = append(attempts, a)
+	}
+	return attempts, rows.Err()
+}
+
+// canEditQuiz は小テストを編集できる（アイテムの作成者かコースの講師）かを判定する
+func canEditQuiz(item model.Item, userEmail string) (bool, error) {
+	if userEmail != "" && item.CreatedBy == userEmail {
+		return true, nil
+	}
+	return isCourseStaff(item.CourseID, userEmail)
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"db/database"
+	"db/model"
+	"strings"
+)
+
+// attachReactions は呼び出したユーザーのいいね・ブックマーク・評価・学習完了の状態を設定する
+func attachReactions(items []model.Item, userEmail string) error {
+	if len(items) == 0 || userEmail == "" {
+		return nil
+	}
+
+	index := make(map[string]int, len(items))
+	params := []interface{}{userEmail}
+	for i, item := range items {
+		index[item.ID] = i
+		params = append(params, item.ID)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(items)), ", ")
+
+	for _, table := range []string{"item_likes", "item_bookmarks", "item_progress"} {
+		rows, err := database.Db.Query("SELECT itemId FROM "+table+" WHERE userEmail = ? AND itemId IN ("+placeholders+")", params...)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var itemID string
+			if err := rows.Scan(&itemID); err != nil {
+				rows.Close()
+				return err
+			}
+			switch table {
+			case "item_likes":
+				items[index[itemID]].Liked = true
+			case "item_bookmarks":
+				items[index[itemID]].Bookmarked = true
+			case "item_progress":
+				items[index[itemID]].Completed = true
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+	}
+
+	rows, err := database.Db.Query("SELECT itemId, score FROM item_ratings WHERE userEmail = ? AND itemId IN ("+placeholders+")", params...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var itemID string
+		var score int
+		if err := rows.Scan(&itemID, &score); err != nil {
+			return err
+		}
+		items[index[itemID]].MyRating = score
+	}
+	return rows.Err()
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"database/sql"
+	"db/database"
+	"db/model"
+	"db/textindex"
+	"log"
+	"sort"
+)
+
+// 関連アイテムの類似度の重み（合計 1）
+const (
+	relatedContentWeight  = 0.5
+	relatedTagWeight      = 0.3
+	relatedCategoryWeight = 0.1
+	relatedChapterWeight  = 0.1
+)
+
+// 関連アイテムとして保存する件数と、保存する類似度の下限
+const (
+	maxRelatedItems = 20
+	minRelatedScore = 0.05
+)
+
+// RelatedItem は関連アイテムとその類似度
+type RelatedItem struct {
+	model.Item
+	RelatedScore float64 `json:"relatedScore"`
+}
+
+// itemSimilarity は本文の TF-IDF ベクトル・タグ・カテゴリ・章から2つのアイテムの類似度を求める
+func itemSimilarity(a, b courseDocument, va, vb textindex.Vector) float64 {
+	score := relatedContentWeight * va.Cosine(vb)
+	score += relatedTagWeight * tagJaccard(a.Tags, b.Tags)
+	if a.Category == b.Category {
+		score += relatedCategoryWeight
+	}
+	if a.Chapter == b.Chapter {
+		score += relatedChapterWeight
+	}
+	return score
+}
+
+// tagJaccard はタグの集合の Jaccard 係数を返す
+func tagJaccard(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	set := map[string]bool{}
+	for _, t := range a {
+		set[t] = true
+	}
+	shared := 0
+	for _, t := range b {
+		if set[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// refreshRelatedItems はアイテムの関連アイテムを計算し直して保存する
+// 相手のアイテムの関連アイテムではこのアイテムの類似度だけを更新し（他の関連アイテムは残す）、件数が上限を超えた分は類似度の低いものから削除する
+// リクエストの応答を遅らせないよう、アイテムの追加・更新時は goroutine から呼び出す
+func refreshRelatedItems(itemID string) {
+	if err := computeRelatedItems(itemID); err != nil {
+		log.Printf("Error: failed to refresh related items for %s: %v\n", itemID, err)
+	}
+}
+
+func computeRelatedItems(itemID string) error {
+	var courseID string
+	if err := database.Db.QueryRow("SELECT courseId FROM items WHERE id = ?", itemID).Scan(&courseID); err != nil {
+		return err
+	}
+	// 閲覧できるかどうかは取得時に絞り込むため、公開状態に関係なくコース内のすべてのアイテムを対象にする
+	docs, err := queryCourseDocuments(courseID, "TRUE")
+	if err != nil {
+		return err
+	}
+
+	corpus := textindex.NewCorpus()
+	target := -1
+	for i, d := range docs {
+		corpus.Add(d.Tokens)
+		if d.ID == itemID {
+			target = i
+		}
+	}
+	if target < 0 {
+		// コーパスの上限より古いアイテムは対象外（取得のたびに計算し直さないよう計算済みにする）
+		return markRelatedItemsComputed(database.Db, itemID)
+	}
+	targetVector := corpus.Vector(docs[target].Tokens)
+
+	type scored struct {
+		id    string
+		score float64
+	}
+	var related []scored
+	for i, d := range docs {
+		if i == target {
+			continue
+		}
+		if s := itemSimilarity(docs[target], d, targetVector, corpus.Vector(d.Tokens)); s >= minRelatedScore {
+			related = append(related, scored{d.ID, s})
+		}
+	}
+	sort.Slice(related, func(i, j int) bool { return related[i].score > related[j].score })
+
+	tx, err := database.Db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// 自分の関連アイテムは上位の件数だけを保存し直す
+	if _, err := tx.Exec("DELETE FROM item_related WHERE itemId = ?", itemID); err != nil {
+		return err
+	}
+	for i, r := range related {
+		if i == maxRelatedItems {
+			break
+		}
+		_, err := tx.Exec("INSERT INTO item_related (itemId, relatedId, score) VALUES (?, ?, ?)", itemID, r.id, r.score)
+		if err != nil {
+			return err
+		}
+	}
+
+	// 相手の関連アイテムでは、このアイテムの類似度だけを更新する
+	// 他の関連アイテムはそのまま残し、相手の上位に入る場合のみ追加する
+	if _, err := tx.Exec("DELETE FROM item_related WHERE relatedId = ?", itemID); err != nil {
+		return err
+	}
+	type relatedList struct {
+		count    int
+		minScore float64
+	}
+	lists := map[string]relatedList{}
+	rows, err := tx.Query("SELECT r.itemId, COUNT(*), MIN(r.score) FROM item_related r JOIN items ON items.id = r.itemId WHERE items.courseId = ? GROUP BY r.itemId", courseID)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var id string
+		var l relatedList
+		if err := rows.Scan(&id, &l.count, &l.minScore); err != nil {
+			rows.Close()
+			return err
+		}
+		lists[id] = l
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, r := range related {
+		if l := lists[r.id]; l.count >= maxRelatedItems && r.score <= l.minScore {
+			continue
+		}
+		_, err := tx.Exec("INSERT INTO item_related (itemId, relatedId, score) VALUES (?, ?, ?)", r.id, itemID, r.score)
+		if err != nil {
+			return err
+		}
+		// 相手の関連アイテムは上限の件数までにする
+		_, err = tx.Exec(`DELETE FROM item_related WHERE itemId = ? AND score < (
+			SELECT score FROM (SELECT score FROM item_related WHERE itemId = ? ORDER BY score DESC LIMIT 1 OFFSET ?) s)`,
+			r.id, r.id, maxRelatedItems-1)
+		if err != nil {
+			return err
+		}
+	}
+	if err := markRelatedItemsComputed(tx, itemID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// relatedItems は保存された関連アイテムのうち、ユーザーが閲覧できるものを類似度の高い順に返す
+func relatedItems(itemID, userEmail string, limit int) ([]RelatedItem, error) {
+	readableSQL, readableParams := readableItemsCondition(userEmail)
+	params := append([]interface{}{itemID}, readableParams...)
+	rows, err := database.Db.Query("SELECT "+itemColumns+", r.score FROM items JOIN item_related r ON r.relatedId = items.id"+
+		" WHERE r.itemId = ? AND "+readableSQL+" ORDER BY r.score DESC, items.id LIMIT ?", append(params, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	related := []RelatedItem{}
+	for rows.Next() {
+		var r RelatedItem
+		item, err := scanItem(extraScanner{s: rows, extra: []interface{}{&r.RelatedScore}})
+		if err != nil {
+			return nil, err
+		}
+		r.Item = item
+		related = append(related, r)
+	}
+	return related, rows.Err()
+}
+
+// hasRelatedItems は関連アイテムが計算済みかどうかを返す
+// 類似度の下限を超えるアイテムがなく item_related に行がない場合も、計算済みとして扱う
+func hasRelatedItems(itemID string) (bool, error) {
+	var count int
+	err := database.Db.QueryRow("SELECT COUNT(*) FROM item_related_computed WHERE itemId = ?", itemID).Scan(&count)
+	return count > 0, err
+}
+
+// execer は *sql.DB と *sql.Tx の共通部分
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// markRelatedItemsComputed は関連アイテムを計算した日時を記録する
+func markRelatedItemsComputed(e execer, itemID string) error {
+	_, err := e.Exec("REPLACE INTO item_related_computed (itemId, computedAt) VALUES (?, UTC_TIMESTAMP())", itemID)
+	return err
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"db/database"
+)
+
+// 複数のレプリカで同時に実行されないようにするためのロック名
+const publishSchedulerLock = "publish_scheduler"
+
+// PublishScheduledItems は予約日時を過ぎたアイテムを公開中に切り替え、切り替えた件数を返す
+// MySQL の GET_LOCK で排他し、他のレプリカが実行中の場合は何もしない
+func PublishScheduledItems(ctx context.Context) (int64, error) {
+	// GET_LOCK は接続単位のロックのため、同じ接続で取得・更新・解放する
+	conn, err := database.Db.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	var locked sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 0)", publishSchedulerLock).Scan(&locked); err != nil {
+		return 0, err
+	}
+	if !locked.Valid || locked.Int64 != 1 {
+		return 0, nil
+	}
+	defer conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", publishSchedulerLock)
+
+	result, err := conn.ExecContext(ctx,
+		"UPDATE items SET status = ?, publishAt = NULL, updatedAt = NOW() WHERE status = ? AND publishAt <= UTC_TIMESTAMP()",
+		statusPublished, statusScheduled)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
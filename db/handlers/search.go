@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"db/markdown"
+	"strings"
+)
+
+// searchFilter は検索系のエンドポイントで共通の検索条件
+type searchFilter struct {
+	CourseID   string  `json:"courseId"`
+	SearchTerm string  `json:"searchTerm"`
+	Category   string  `json:"category"`
+	Chapter    string  `json:"chapter"`
+	SortOption string  `json:"sortOption"`
+	UserEmail  string  `json:"userEmail"`
+	MinRating  float64 `json:"minRating"` // 評価の平均がこの値以上のもののみ（0の場合は絞り込まない）
+	// true の場合は userEmail のユーザーがまだ完了していないアイテムのみ
+	NotCompleted bool `json:"notCompleted"`
+	// タグで絞り込む場合のタグと、その条件（any: いずれか、all: すべて。省略時は any）
+	Tags    []string `json:"tags"`
+	TagMode string   `json:"tagMode"`
+	// 本文のコードブロックの言語（例: go, python）で絞り込む
+	Language string `json:"language"`
+}
+
+// buildSearchQuery は検索条件からパラメータ化されたSQLクエリを構築する
+// conditions と params には呼び出し元ごとの追加のWHERE条件を渡す
+func buildSearchQuery(f searchFilter, conditions []string, params []interface{}) (string, []interface{}) {
+	// 検索は常に呼び出し元が選択しているコースの中で行う
+	conditions = append([]string{"courseId = ?", "title LIKE ?"}, conditions...)
+	params = append([]interface{}{f.CourseID, "%" + f.SearchTerm + "%"}, params...)
+
+	// カテゴリと章の選択肢が空でない場合、それらをクエリに追加
+	if f.Category != "" {
+		conditions = append(conditions, "category = ?")
+		params = append(params, f.Category)
+	}
+	if f.Chapter != "" {
+		conditions = append(conditions, "chapter = ?")
+		params = append(params, f.Chapter)
+	}
+
+	if f.MinRating > 0 {
+		conditions = append(conditions, "ratingCount > 0 AND ratingAvg >= ?")
+		params = append(params, f.MinRating)
+	}
+
+	var tags []string
+	seenTags := map[string]bool{}
+	for _, tag := range f.Tags {
+		if tag = normalizeTag(tag); tag != "" && !seenTags[tag] {
+			seenTags[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > 0 {
+		tagSQL, tagParams := tagCondition(tags, f.TagMode)
+		conditions = append(conditions, tagSQL)
+		params = append(params, tagParams...)
+	}
+
+	if language := markdown.NormalizeLanguage(f.Language); language != "" {
+		conditions = append(conditions, "id IN (SELECT itemId FROM item_snippets WHERE language = ?)")
+		params = append(params, language)
+	}
+
+	if f.NotCompleted {
+		conditions = append(conditions, "id NOT IN (SELECT itemId FROM item_progress WHERE userEmail = ?)")
+		params = append(params, f.UserEmail)
+	}
+
+	// ソートオプションに応じて適切なORDER BY句を追加
+	sortSQL := ""
+	switch f.SortOption {
+	case "createdAt":
+		sortSQL = " ORDER BY createdAt DESC"
+	case "-createdAt":
+		sortSQL = " ORDER BY createdAt"
+	case "updatedAt":
+		sortSQL = " ORDER BY updatedAt DESC"
+	case "-updatedAt":
+		sortSQL = " ORDER BY updatedAt"
+	case "likes":
+		sortSQL = " ORDER BY likeCount DESC, createdAt DESC"
+	case "-likes":
+		sortSQL = " ORDER BY likeCount, createdAt DESC"
+	case "rating":
+		sortSQL = " ORDER BY ratingAvg DESC, ratingCount DESC"
+	case "-rating":
+		sortSQL = " ORDER BY ratingAvg, ratingCount DESC"
+	}
+
+	return "SELECT " + itemColumns + " FROM items WHERE " + strings.Join(conditions, " AND ") + sortSQL, params
+}
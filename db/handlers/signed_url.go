@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// 署名付きURLの有効期間
+const downloadURLTTL = 15 * time.Minute
+
+// 署名に使う秘密鍵（環境変数 DOWNLOAD_URL_SECRET から取得）
+var downloadURLSecret = []byte(os.Getenv("DOWNLOAD_URL_SECRET"))
+
+var errSigningDisabled = errors.New("DOWNLOAD_URL_SECRET is not set")
+
+// downloadSignature はキーと有効期限に対するHMAC-SHA256署名を計算する
+func downloadSignature(key string, expires int64) string {
+	mac := hmac.New(sha256.New, downloadURLSecret)
+	mac.Write([]byte(key + "\n" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// signDownloadURL はストレージのキーから期限付きのダウンロードURLを作成する
+func signDownloadURL(key string, ttl time.Duration) (string, time.Time, error) {
+	if len(downloadURLSecret) == 0 {
+		return "", time.Time{}, errSigningDisabled
+	}
+	expiresAt := time.Now().Add(ttl)
+	expires := expiresAt.Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("sig", downloadSignature(key, expires))
+	path := (&url.URL{Path: filesPath + key}).EscapedPath()
+	return path + "?" + query.Encode(), expiresAt, nil
+}
+
+// fileURL はストレージのキーから期限付きの配信用URLを作成する（失敗時は空文字）
+func fileURL(key string) string {
+	u, _, err := signDownloadURL(key, downloadURLTTL)
+	if err != nil {
+		log.Printf("Error: %v\n", err)
+		return ""
+	}
+	return u
+}
+
+// verifyDownloadURL は署名と有効期限を検証する
+func verifyDownloadURL(key, expiresStr, sig string) bool {
+	if len(downloadURLSecret) == 0 {
+		return false
+	}
+	expires, err := strconv.ParseInt(expiresStr, 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(downloadSignature(key, expires)))
+}
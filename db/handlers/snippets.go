@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"db/database"
+	"db/markdown"
+)
+
+// LanguageCount はコードの言語と、その言語のコードを含む閲覧できるアイテムの数
+type LanguageCount struct {
+	Language string `json:"language"`
+	Count    int    `json:"count"`
+}
+
+// saveItemSnippets は本文のコードブロックを言語で検索できるように保存する
+func saveItemSnippets(itemID, content string) error {
+	tx, err := database.Db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM item_snippets WHERE itemId = ?", itemID); err != nil {
+		return err
+	}
+	for i, block := range markdown.CodeBlocks(content) {
+		_, err := tx.Exec("INSERT INTO item_snippets (itemId, position, language, code, line) VALUES (?, ?, ?, ?, ?)",
+			itemID, i, block.Language, block.Code, block.Line)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// snippetLanguages はコース内の閲覧できるアイテムで使われているコードの言語を、アイテムの多い順に返す
+func snippetLanguages(courseID, userEmail string) ([]LanguageCount, error) {
+	readableSQL, readableParams := readableItemsCondition(userEmail)
+	params := append([]interface{}{courseID}, readableParams...)
+	rows, err := database.Db.Query(`
+		SELECT s.language, COUNT(DISTINCT s.itemId) AS uses
+		FROM item_snippets s JOIN items ON items.id = s.itemId
+		WHERE items.courseId = ? AND s.language != '' AND `+readableSQL+`
+		GROUP BY s.language
+		ORDER BY uses DESC, s.language`, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	languages := []LanguageCount{}
+	for rows.Next() {
+		var l LanguageCount
+		if err := rows.Scan(&l.Language, &l.Count); err != nil {
+			return nil, err
+		}
+		languages = append(languages, l)
+	}
+	return languages, rows.Err()
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"db/database"
+	"db/textindex"
+	"math"
+	"sort"
+)
+
+// 内容からの提案に使う値
+const (
+	maxCorpusDocuments       = 2000 // コーパスに使うアイテムの上限（新しく更新された順）
+	suggestionNeighbors      = 10   // 提案の根拠にする類似アイテムの数
+	maxTagSuggestionsPerItem = 5
+	minSuggestionScore       = 0.05
+	tagNameMatchBoost        = 0.3 // タグ名の語がすべて本文に含まれる場合に加える値
+)
+
+// courseDocument は類似度の計算に使うアイテムの情報
+type courseDocument struct {
+	ID       string
+	Category string
+	Chapter  string
+	Tags     []string
+	Tokens   []string
+}
+
+// documentTokens はアイテムのタイトルと本文をトークンに分割する（タイトルは本文より重く扱うため2回含める）
+func documentTokens(title, content string) []string {
+	titleTokens := textindex.Tokenize(title)
+	tokens := append(titleTokens, titleTokens...)
+	return append(tokens, textindex.Tokenize(content)...)
+}
+
+// loadCourseDocuments はコース内でユーザーが閲覧できるアイテムをタグ付きで読み込む
+func loadCourseDocuments(courseID, userEmail string) ([]courseDocument, error) {
+	readableSQL, readableParams := readableItemsCondition(userEmail)
+	return queryCourseDocuments(courseID, readableSQL, readableParams...)
+}
+
+// queryCourseDocuments はコース内で condition に一致するアイテムをタグ付きで読み込む
+func queryCourseDocuments(courseID, condition string, conditionParams ...interface{}) ([]courseDocument, error) {
+	params := append([]interface{}{courseID}, conditionParams...)
+	rows, err := database.Db.Query("SELECT id, title, content, category, chapter FROM items WHERE courseId = ? AND "+condition+" ORDER BY updatedAt DESC LIMIT ?",
+		append(params, maxCorpusDocuments)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	docs := []courseDocument{}
+	index := map[string]int{}
+	for rows.Next() {
+		var d courseDocument
+		var title, content string
+		if err := rows.Scan(&d.ID, &title, &content, &d.Category, &d.Chapter); err != nil {
+			return nil, err
+		}
+		d.Tokens = documentTokens(title, content)
+		index[d.ID] = len(docs)
+		docs = append(docs, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tagRows, err := database.Db.Query("SELECT it.itemId, t.name FROM item_tags it JOIN tags t ON t.id = it.tagId WHERE t.courseId = ?", courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer tagRows.Close()
+	for tagRows.Next() {
+		var itemID, name string
+		if err := tagRows.Scan(&itemID, &name); err != nil {
+			return nil, err
+		}
+		if i, ok := index[itemID]; ok {
+			docs[i].Tags = append(docs[i].Tags, name)
+		}
+	}
+	return docs, tagRows.Err()
+}
+
+// Suggestion は提案する名前とその確からしさ
+type Suggestion struct {
+	Name  string  `json:"name"`
+	Score float64 `json:"score"`
+}
+
+// ContentSuggestions はタイトルと本文から提案するタグ・カテゴリ・章
+// Keywords はまだタグとして使われていない特徴的な語（新しいタグの候補）
+type ContentSuggestions struct {
+	Tags     []Suggestion `json:"tags"`
+	Keywords []string     `json:"keywords"`
+	Category *Suggestion  `json:"category"`
+	Chapter  *Suggestion  `json:"chapter"`
+}
+
+// suggestFromContent はコース内の既存のアイテムをコーパスにした TF-IDF で、内容の似たアイテムのタグ・カテゴリ・章を提案する
+func suggestFromContent(docs []courseDocument, title, content string) ContentSuggestions {
+	suggestions := ContentSuggestions{Tags: []Suggestion{}, Keywords: []string{}}
+
+	corpus := textindex.NewCorpus()
+	for _, d := range docs {
+		corpus.Add(d.Tokens)
+	}
+	query := corpus.Vector(documentTokens(title, content))
+	if len(query) == 0 {
+		return suggestions
+	}
+
+	// 内容の似たアイテム
+	type neighbor struct {
+		doc        courseDocument
+		similarity float64
+	}
+	var neighbors []neighbor
+	for _, d := range docs {
+		if s := query.Cosine(corpus.Vector(d.Tokens)); s > 0 {
+			neighbors = append(neighbors, neighbor{d, s})
+		}
+	}
+	sort.Slice(neighbors, func(i, j int) bool { return neighbors[i].similarity > neighbors[j].similarity })
+	if len(neighbors) > suggestionNeighbors {
+		neighbors = neighbors[:suggestionNeighbors]
+	}
+
+	// 似たアイテムの類似度で、タグ・カテゴリ・章に投票する
+	tagScores := map[string]float64{}
+	categoryScores := map[string]float64{}
+	chapterScores := map[string]float64{}
+	for _, n := range neighbors {
+		for _, tag := range n.doc.Tags {
+			tagScores[tag] += n.similarity
+		}
+		categoryScores[n.doc.Category] += n.similarity
+		chapterScores[n.doc.Chapter] += n.similarity
+	}
+
+	// 既存のタグの名前が本文に含まれている場合も候補にする
+	knownTags := map[string]bool{}
+	for _, d := range docs {
+		for _, tag := range d.Tags {
+			knownTags[tag] = true
+		}
+	}
+	for tag := range knownTags {
+		tokens := textindex.Tokenize(tag)
+		matched := len(tokens) > 0
+		for _, t := range tokens {
+			if _, ok := query[t]; !ok {
+				matched = false
+			}
+		}
+		if matched {
+			tagScores[tag] += tagNameMatchBoost
+		}
+	}
+
+	suggestions.Tags = rankSuggestions(tagScores, maxTagSuggestionsPerItem)
+	if best := rankSuggestions(categoryScores, 1); len(best) > 0 {
+		suggestions.Category = &best[0]
+	}
+	if best := rankSuggestions(chapterScores, 1); len(best) > 0 {
+		suggestions.Chapter = &best[0]
+	}
+
+	for _, term := range query.Top(len(query)) {
+		if len(suggestions.Keywords) >= maxTagSuggestionsPerItem {
+			break
+		}
+		if textindex.IsWord(term) && !knownTags[term] {
+			suggestions.Keywords = append(suggestions.Keywords, term)
+		}
+	}
+	return suggestions
+}
+
+// rankSuggestions はスコアの高い順に n 件を返す（低すぎるものは含めない）
+func rankSuggestions(scores map[string]float64, n int) []Suggestion {
+	ranked := []Suggestion{}
+	for name, score := range scores {
+		if name != "" && score >= minSuggestionScore {
+			ranked = append(ranked, Suggestion{Name: name, Score: math.Round(score*1000) / 1000})
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Name < ranked[j].Name
+	})
+	if len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"database/sql"
+	"db/database"
+	"db/model"
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// タグの文字数とアイテムごとのタグの数の上限
+const (
+	maxTagLength   = 50
+	maxTagsPerItem = 20
+)
+
+// タグの絞り込みの方法
+const (
+	tagModeAny = "any" // いずれかのタグが付いている
+	tagModeAll = "all" // すべてのタグが付いている
+)
+
+var errInvalidTags = errors.New("invalid tags")
+
+// TagCount はタグと、それが付いている閲覧できるアイテムの数
+type TagCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// normalizeTag は前後・連続する空白と先頭の # を取り除き、英字を小文字にそろえる
+func normalizeTag(tag string) string {
+	tag = strings.TrimLeft(strings.TrimSpace(tag), "#＃")
+	return strings.ToLower(strings.Join(strings.Fields(tag), " "))
+}
+
+// normalizeTags はタグを正規化して重複と空のタグを取り除く
+// 長すぎるタグや多すぎるタグは errInvalidTags を返す
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := []string{}
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		tag = normalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, errInvalidTags
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > maxTagsPerItem {
+		return nil, errInvalidTags
+	}
+	return normalized, nil
+}
+
+// setItemTags はアイテムのタグを置き換える（タグはコースごとに作成する）
+// アイテムの保存と同じトランザクションで呼び出す
+func setItemTags(tx *sql.Tx, courseID, itemID string, tags []string) error {
+	if _, err := tx.Exec("DELETE FROM item_tags WHERE itemId = ?", itemID); err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		id, err := generateULID()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec("INSERT IGNORE INTO tags (id, courseId, name) VALUES (?, ?, ?)", id, courseID, tag); err != nil {
+			return err
+		}
+		_, err = tx.Exec("INSERT IGNORE INTO item_tags (itemId, tagId) SELECT ?, id FROM tags WHERE courseId = ? AND name = ?", itemID, courseID, tag)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attachTags はアイテムのスライスにタグを設定する
+func attachTags(items []model.Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	index := make(map[string]int, len(items))
+	params := make([]interface{}, 0, len(items))
+	for i := range items {
+		items[i].Tags = []string{}
+		index[items[i].ID] = i
+		params = append(params, items[i].ID)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(items)), ", ")
+
+	rows, err := database.Db.Query("SELECT it.itemId, t.name FROM item_tags it JOIN tags t ON t.id = it.tagId WHERE it.itemId IN ("+placeholders+") ORDER BY t.name", params...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var itemID, name string
+		if err := rows.Scan(&itemID, &name); err != nil {
+			return err
+		}
+		item := &items[index[itemID]]
+		item.Tags = append(item.Tags, name)
+	}
+	return rows.Err()
+}
+
+// tagCondition はタグで絞り込むためのWHERE条件
+// mode が all の場合はすべてのタグ、それ以外はいずれかのタグが付いているアイテムを返す
+// 結合したクエリでも使えるよう、列は items. を付けて指定する
+func tagCondition(tags []string, mode string) (string, []interface{}) {
+	params := make([]interface{}, 0, len(tags)+1)
+	for _, tag := range tags {
+		params = append(params, tag)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(tags)), ", ")
+	query := "items.id IN (SELECT it.itemId FROM item_tags it JOIN tags t ON t.id = it.tagId WHERE t.name IN (" + placeholders + ")"
+	if mode == tagModeAll {
+		return query + " GROUP BY it.itemId HAVING COUNT(DISTINCT t.name) = ?)", append(params, len(tags))
+	}
+	return query + ")", params
+}
+
+// suggestTags は前方一致するタグを、閲覧できるアイテムでの使用数の多い順に返す
+// tags と items の両方に courseId があるため、閲覧できるかの条件は items. を付けた列で判定する
+func suggestTags(courseID, userEmail, prefix string, limit int) ([]TagCount, error) {
+	readableSQL, readableParams := readableItemsCondition(userEmail)
+	params := append([]interface{}{courseID, escapeLike(normalizeTag(prefix)) + "%"}, readableParams...)
+	rows, err := database.Db.Query(`
+		SELECT t.name, COUNT(*) AS uses
+		FROM tags t
+		JOIN item_tags it ON it.tagId = t.id
+		JOIN items ON items.id = it.itemId
+		WHERE t.courseId = ? AND t.name LIKE ? AND `+readableSQL+`
+		GROUP BY t.id, t.name
+		ORDER BY uses DESC, t.name
+		LIMIT ?`, append(params, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []TagCount{}
+	for rows.Next() {
+		var t TagCount
+		if err := rows.Scan(&t.Name, &t.Count); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+	return tags, rows.Err()
+}
+
+// escapeLike は LIKE の特殊文字をエスケープする
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// mergeTags は sources のタグを target にまとめる（target がなければ作成する）
+// target と同じ名前のタグは sources に含まれていても無視する
+func mergeTags(courseID string, sources []string, target string) (int64, error) {
+	tx, err := database.Db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	id, err := generateULID()
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec("INSERT IGNORE INTO tags (id, courseId, name) VALUES (?, ?, ?)", id, courseID, target); err != nil {
+		return 0, err
+	}
+	var targetID string
+	if err := tx.QueryRow("SELECT id FROM tags WHERE courseId = ? AND name = ?", courseID, target).Scan(&targetID); err != nil {
+		return 0, err
+	}
+
+	var moved int64
+	for _, source := range sources {
+		if source == target {
+			continue
+		}
+		var sourceID string
+		err := tx.QueryRow("SELECT id FROM tags WHERE courseId = ? AND name = ?", courseID, source).Scan(&sourceID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				continue
+			}
+			return 0, err
+		}
+		result, err := tx.Exec("INSERT IGNORE INTO item_tags (itemId, tagId) SELECT itemId, ? FROM item_tags WHERE tagId = ?", targetID, sourceID)
+		if err != nil {
+			return 0, err
+		}
+		n, _ := result.RowsAffected()
+		moved += n
+		if _, err := tx.Exec("DELETE FROM item_tags WHERE tagId = ?", sourceID); err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec("DELETE FROM tags WHERE id = ?", sourceID); err != nil {
+			return 0, err
+		}
+	}
+	return moved, tx.Commit()
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"bytes"
+	"db/database"
+	"db/model"
+	"db/storage"
+	"db/thumbnail"
+	"log"
+	"strings"
+)
+
+// アップロードされたファイルのキーの接頭辞
+const storedFilePrefix = "files/"
+
+// isStoredFile はファイルの値がストレージ上のキーかどうかを判定する
+// （外部URLが登録されている既存アイテムはそのまま扱う）
+func isStoredFile(file string) bool {
+	return strings.HasPrefix(file, storedFilePrefix)
+}
+
+// generateThumbnails は画像ファイルのサムネイルを生成してストレージに保存する
+// リクエストの応答を遅らせないよう goroutine から呼び出す
+func generateThumbnails(itemID, file, fileType string) {
+	if !isStoredFile(file) || !strings.HasPrefix(fileType, "image") {
+		return
+	}
+
+	f, err := storage.Store.Open(file)
+	if err != nil {
+		log.Printf("Error: failed to open %s for thumbnails: %v\n", file, err)
+		return
+	}
+	defer f.Close()
+
+	images, err := thumbnail.Generate(f)
+	if err == thumbnail.ErrTooLarge {
+		log.Printf("Skipped thumbnails for %s: %v\n", itemID, err)
+		return
+	}
+	if err != nil {
+		log.Printf("Error: failed to generate thumbnails for %s: %v\n", itemID, err)
+		return
+	}
+
+	for size, data := range images {
+		key := "thumbnails/" + itemID + "/" + size + ".jpg"
+		if err := storage.Store.Put(key, bytes.NewReader(data)); err != nil {
+			log.Printf("Error: failed to store thumbnail %s: %v\n", key, err)
+			return
+		}
+		_, err := database.Db.Exec("REPLACE INTO thumbnails (itemId, size, objectKey) VALUES (?, ?, ?)", itemID, size, key)
+		if err != nil {
+			log.Printf("Error: failed to save thumbnail %s: %v\n", key, err)
+			return
+		}
+	}
+}
+
+// attachThumbnails はアイテムのスライスにサムネイルのURLを設定する
+func attachThumbnails(items []model.Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	index := make(map[string]int, len(items))
+	placeholders := make([]string, 0, len(items))
+	params := make([]interface{}, 0, len(items))
+	for i, item := range items {
+		index[item.ID] = i
+		placeholders = append(placeholders, "?")
+		params = append(params, item.ID)
+	}
+
+	rows, err := database.Db.Query("SELECT itemId, size, objectKey FROM thumbnails WHERE itemId IN ("+strings.Join(placeholders, ", ")+")", params...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var itemID, size, key string
+		if err := rows.Scan(&itemID, &size, &key); err != nil {
+			return err
+		}
+		item := &items[index[itemID]]
+		if item.Thumbnails == nil {
+			item.Thumbnails = map[string]string{}
+		}
+		item.Thumbnails[size] = fileURL(key)
+	}
+	return rows.Err()
+}
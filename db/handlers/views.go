@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"db/database"
+	"db/model"
+	"time"
+)
+
+// 同じユーザーの閲覧を1回として数える時間
+const viewDedupWindow = 30 * time.Minute
+
+// recordView はアイテムの閲覧を記録し、日別の閲覧数に加算したかどうかを返す
+// viewerKey ごとに viewDedupWindow 以内の閲覧は数えない
+func recordView(itemID, viewerKey string) (bool, error) {
+	tx, err := database.Db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// 新規は1、最終閲覧から時間が経っていて更新した場合は2、重複で変更なしの場合は0が返る
+	result, err := tx.Exec(`
+		INSERT INTO item_view_marks (itemId, viewerKey, lastViewedAt) VALUES (?, ?, UTC_TIMESTAMP())
+		ON DUPLICATE KEY UPDATE lastViewedAt = IF(lastViewedAt <= UTC_TIMESTAMP() - INTERVAL ? SECOND, VALUES(lastViewedAt), lastViewedAt)`,
+		itemID, viewerKey, int(viewDedupWindow.Seconds()))
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if n == 0 {
+		return false, nil
+	}
+
+	_, err = tx.Exec("INSERT INTO item_view_daily (itemId, day, views) VALUES (?, UTC_DATE(), 1) ON DUPLICATE KEY UPDATE views = views + 1", itemID)
+	if err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// TrendingItem は人気順のランキングに表示するアイテム
+type TrendingItem struct {
+	model.Item
+	TrendScore float64 `json:"trendScore"`
+}
+
+// extraScanner は scanItem の列に続けて追加の列を読み込むための scanner
+type extraScanner struct {
+	s     scanner
+	extra []interface{}
+}
+
+func (e extraScanner) Scan(dest ...interface{}) error {
+	return e.s.Scan(append(dest, e.extra...)...)
+}
+
+// trendingItems は直近 days 日の閲覧数を半減期 halfLife 日で減衰させたスコアの高い順にアイテムを返す
+func trendingItems(f searchFilter, days int, halfLife float64) ([]TrendingItem, error) {
+	readableSQL, readableParams := readableItemsCondition(f.UserEmail)
+	query := "SELECT " + itemColumns + ", s.score FROM items JOIN (" +
+		"SELECT itemId, SUM(views * POW(0.5, DATEDIFF(UTC_DATE(), day) / ?)) AS score FROM item_view_daily" +
+		" WHERE day > UTC_DATE() - INTERVAL ? DAY GROUP BY itemId" +
+		") s ON s.itemId = items.id WHERE courseId = ? AND " + readableSQL
+	params := append([]interface{}{halfLife, days, f.CourseID}, readableParams...)
+	if f.Category != "" {
+		query += " AND category = ?"
+		params = append(params, f.Category)
+	}
+	if f.Chapter != "" {
+		query += " AND chapter = ?"
+		params = append(params, f.Chapter)
+	}
+	query += " ORDER BY s.score DESC"
+
+	rows, err := database.Db.Query(query, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	trending := []TrendingItem{}
+	for rows.Next() {
+		var t TrendingItem
+		item, err := scanItem(extraScanner{s: rows, extra: []interface{}{&t.TrendScore}})
+		if err != nil {
+			return nil, err
+		}
+		t.Item = item
+		trending = append(trending, t)
+	}
+	return trending, rows.Err()
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"database/sql"
+	"db/database"
+	"db/model"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// アイテムの公開状態を変更する操作
+const (
+	actionSubmit  = "submit"  // 作成者がレビューを依頼する
+	actionApprove = "approve" // レビュアーが承認して公開（予約日時が未来なら予約公開）する
+	actionReject  = "reject"  // レビュアーがコメント付きで差し戻す
+	actionPublish = "publish" // レビュアーが直接公開する
+	actionArchive = "archive" // 作成者がアーカイブする
+)
+
+// itemTransition は操作ごとの遷移元・遷移先と実行できる役割
+type itemTransition struct {
+	from     []string
+	to       string
+	reviewer bool // true の場合はレビュアーのみ、false の場合は作成者のみ実行できる
+}
+
+// itemTransitions は公開状態の状態遷移表
+// 作成者が自分で公開する遷移は存在せず、公開には必ずレビュアーを経由する
+var itemTransitions = map[string]itemTransition{
+	actionSubmit:  {from: []string{statusDraft, statusRejected}, to: statusInReview},
+	actionApprove: {from: []string{statusInReview}, to: statusPublished, reviewer: true},
+	actionReject:  {from: []string{statusInReview}, to: statusRejected, reviewer: true},
+	actionPublish: {from: []string{statusArchived}, to: statusPublished, reviewer: true},
+	actionArchive: {from: []string{statusDraft, statusRejected, statusScheduled, statusPublished}, to: statusArchived},
+}
+
+var (
+	errTransitionForbidden = errors.New("not allowed to perform this action")
+	errInvalidTransition   = errors.New("invalid status transition")
+	errCommentRequired     = errors.New("comment is required")
+)
+
+// isReviewer は環境変数 REVIEWER_EMAILS（カンマ区切り）に含まれるユーザーか管理者かを判定する
+func isReviewer(email string) bool {
+	if isAdmin(email) {
+		return true
+	}
+	if email == "" {
+		return false
+	}
+	for _, reviewer := range strings.Split(os.Getenv("REVIEWER_EMAILS"), ",") {
+		if strings.TrimSpace(reviewer) == email {
+			return true
+		}
+	}
+	return false
+}
+
+// statusAfterEdit はアイテムの内容を編集した後の公開状態を返す
+// レビューを経た（または受けている）アイテムを編集した場合はレビュー待ちに戻し、承認されるまで公開しない
+// レビュアーが他のユーザーのアイテムを編集した場合はそのままにする（自分のアイテムは承認できないため戻す）
+func statusAfterEdit(item model.Item, editor string) string {
+	switch item.Status {
+	case statusPublished, statusScheduled, statusInReview:
+		if isReviewer(editor) && editor != item.CreatedBy {
+			return item.Status
+		}
+		return statusInReview
+	}
+	return item.Status
+}
+
+// nextItemStatus は操作を実行した後の公開状態を返す
+func nextItemStatus(item model.Item, action, actor, comment string) (string, error) {
+	t, ok := itemTransitions[action]
+	if !ok {
+		return "", errInvalidTransition
+	}
+
+	// レビュアーは自分のアイテムを承認・差し戻しできない
+	if t.reviewer {
+		if !isReviewer(actor) || actor == item.CreatedBy {
+			return "", errTransitionForbidden
+		}
+	} else if actor == "" || actor != item.CreatedBy {
+		return "", errTransitionForbidden
+	}
+
+	valid := false
+	for _, from := range t.from {
+		if item.Status == from {
+			valid = true
+		}
+	}
+	if !valid {
+		return "", errInvalidTransition
+	}
+	if action == actionReject && strings.TrimSpace(comment) == "" {
+		return "", errCommentRequired
+	}
+
+	if action == actionApprove && item.PublishAt != nil && item.PublishAt.After(time.Now()) {
+		return statusScheduled, nil
+	}
+	return t.to, nil
+}
+
+// applyItemAction は状態遷移を検証して公開状態を更新し、レビュー記録と作成者への通知を保存する
+func applyItemAction(item model.Item, action, actor, comment string) (string, error) {
+	to, err := nextItemStatus(item, action, actor, comment)
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := database.Db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	// 読み込み後に他のリクエストで状態が変わっていた場合は更新しない
+	query := "UPDATE items SET status = ?, updatedAt = NOW() WHERE id = ? AND status = ?"
+	if to == statusPublished || to == statusArchived {
+		query = "UPDATE items SET status = ?, publishAt = NULL, updatedAt = NOW() WHERE id = ? AND status = ?"
+	}
+	result, err := tx.Exec(query, to, item.ID, item.Status)
+	if err != nil {
+		return "", err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return "", err
+	} else if n == 0 {
+		return "", errInvalidTransition
+	}
+
+	if action == actionApprove || action == actionReject {
+		if err := recordReview(tx, item, action, actor, comment); err != nil {
+			return "", err
+		}
+	}
+	if actor != item.CreatedBy {
+		if err := notify(tx, item.CreatedBy, item.ID, reviewMessage(item, action, comment)); err != nil {
+			return "", err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+	return to, nil
+}
+
+// recordReview はレビュー結果を保存する
+func recordReview(tx *sql.Tx, item model.Item, decision, reviewer, comment string) error {
+	id, err := generateULID()
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("INSERT INTO item_reviews (id, itemId, reviewer, decision, comment) VALUES (?, ?, ?, ?, ?)",
+		id, item.ID, reviewer, decision, comment)
+	return err
+}
+
+// reviewMessage は作成者に送る通知の本文を作成する
+func reviewMessage(item model.Item, action, comment string) string {
+	switch action {
+	case actionApprove:
+		return fmt.Sprintf("「%s」が承認されました", item.Title)
+	case actionReject:
+		return fmt.Sprintf("「%s」が差し戻されました: %s", item.Title, comment)
+	case actionPublish:
+		return fmt.Sprintf("「%s」が公開されました", item.Title)
+	}
+	return fmt.Sprintf("「%s」の状態が変更されました", item.Title)
+}
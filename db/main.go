@@ -3,17 +3,21 @@
 package main
 
 import (
+	"context"
 	"db/cors"
 	"db/database"
 	"db/handlers"
+	"db/storage"
 	_ "github.com/go-sql-driver/mysql"
 	"log"
 	"net/http"
 	"os"
+	"time"
 )
 
 func init() {
 	database.Init()
+	storage.Init()
 }
 
 func main() {
@@ -25,7 +29,9 @@ func main() {
 			w.WriteHeader(http.StatusOK)
 			return
 		case http.MethodGet:
-			handlers.HandleGetCategoryNames(w)
+			handlers.HandleGetCategoryNames(w, r)
+		case http.MethodPost:
+			handlers.HandleAddCategoryName(w, r)
 		default:
 			w.WriteHeader(http.StatusMethodNotAllowed)
 		}
@@ -38,7 +44,41 @@ func main() {
 			w.WriteHeader(http.StatusOK)
 			return
 		case http.MethodGet:
-			handlers.HandleGetChapterNames(w)
+			handlers.HandleGetChapterNames(w, r)
+		case http.MethodPost:
+			handlers.HandleAddChapterName(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/courses", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleGetCourses(w, r)
+		case http.MethodPost:
+			handlers.HandleAddCourse(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/courseMembers", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleGetCourseMembers(w, r)
+		case http.MethodPost:
+			handlers.HandleChangeCourseMember(w, r)
+		case http.MethodDelete:
+			handlers.HandleChangeCourseMember(w, r)
 		default:
 			w.WriteHeader(http.StatusMethodNotAllowed)
 		}
@@ -123,6 +163,732 @@ func main() {
 		}
 	})))
 
+	http.Handle("/api/item", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleGetItem(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/exportItems", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodPost:
+			handlers.HandleExportItems(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/groups", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleGetGroups(w, r)
+		case http.MethodPost:
+			handlers.HandleAddGroup(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/groupMembers", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodPost:
+			handlers.HandleChangeGroupMember(w, r)
+		case http.MethodDelete:
+			handlers.HandleChangeGroupMember(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/likeItem", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodPut:
+			handlers.HandleLikeItem(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/bookmarkItem", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodPut:
+			handlers.HandleBookmarkItem(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/myBookmarks", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodPost:
+			handlers.HandleSearchMyBookmarks(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/comments", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleGetComments(w, r)
+		case http.MethodPost:
+			handlers.HandleAddComment(w, r)
+		case http.MethodPut:
+			handlers.HandleUpdateComment(w, r)
+		case http.MethodDelete:
+			handlers.HandleDeleteComment(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/rateItem", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodPut, http.MethodDelete:
+			handlers.HandleRateItem(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/recordView", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodPost:
+			handlers.HandleRecordView(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/trendingItems", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleTrendingItems(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/progress", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleGetProgress(w, r)
+		case http.MethodPut:
+			handlers.HandleSetProgress(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/learningPaths", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleGetLearningPaths(w, r)
+		case http.MethodPost:
+			handlers.HandleAddLearningPath(w, r)
+		case http.MethodPut:
+			handlers.HandleUpdateLearningPath(w, r)
+		case http.MethodDelete:
+			handlers.HandleDeleteLearningPath(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/learningPath", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleGetLearningPath(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/learningPathNavigation", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleLearningPathNavigation(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/itemLinks", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleGetItemLinks(w, r)
+		case http.MethodPost:
+			handlers.HandleAddItemLink(w, r)
+		case http.MethodDelete:
+			handlers.HandleDeleteItemLink(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/chapterGraph", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleGetChapterGraph(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/quiz", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleGetQuiz(w, r)
+		case http.MethodPut:
+			handlers.HandleSaveQuiz(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/quizAttempts", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleGetQuizAttempts(w, r)
+		case http.MethodPost:
+			handlers.HandleSubmitQuiz(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/flashcardDecks", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleGetFlashcardDecks(w, r)
+		case http.MethodPost:
+			handlers.HandleAddFlashcardDeck(w, r)
+		case http.MethodPut:
+			handlers.HandleUpdateFlashcardDeck(w, r)
+		case http.MethodDelete:
+			handlers.HandleDeleteFlashcardDeck(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/flashcardDeck", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleGetFlashcardDeck(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/dueFlashcards", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleGetDueFlashcards(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/reviewFlashcard", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodPost:
+			handlers.HandleReviewFlashcard(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/tags", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleGetTags(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/suggestTags", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodPost:
+			handlers.HandleSuggestTags(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/relatedItems", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleGetRelatedItems(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/renderMarkdown", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodPost:
+			handlers.HandleRenderMarkdown(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/itemSnippets", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleGetItemSnippets(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/snippetLanguages", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleGetSnippetLanguages(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/brokenLinks", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleGetBrokenLinks(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/reportItem", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodPost:
+			handlers.HandleReportItem(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/publishItem", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodPost:
+			handlers.HandlePublishItem(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/archiveItem", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodPost:
+			handlers.HandleArchiveItem(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/submitForReview", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodPost:
+			handlers.HandleSubmitForReview(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/approveItem", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodPost:
+			handlers.HandleApproveItem(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/rejectItem", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodPost:
+			handlers.HandleRejectItem(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/reviewQueue", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleReviewQueue(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/notifications", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleGetNotifications(w, r)
+		case http.MethodPut:
+			handlers.HandleReadNotifications(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/schedulePublish", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodPost:
+			handlers.HandleSchedulePublish(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/uploadFile", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodPost:
+			handlers.HandleUploadFile(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/downloadUrl", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodPost:
+			handlers.HandleGetDownloadURL(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/files/", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleGetFile(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/admin/collectGarbage", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodPost:
+			handlers.HandleCollectGarbage(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/admin/mergeTags", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodPost:
+			handlers.HandleMergeTags(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/admin/renameTag", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodPut:
+			handlers.HandleRenameTag(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/admin/checkLinks", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodPost:
+			handlers.HandleCheckLinks(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/admin/moderationQueue", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleModerationQueue(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/admin/moderateItem", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodPost:
+			handlers.HandleModerateItem(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	http.Handle("/api/admin/moderationLog", cors.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodOptions:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodGet:
+			handlers.HandleModerationLog(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})))
+
+	// GC_INTERVAL が設定されている場合は定期的に未参照ファイルを削除する
+	if interval, err := time.ParseDuration(os.Getenv("GC_INTERVAL")); err == nil && interval > 0 {
+		go func() {
+			for range time.Tick(interval) {
+				report, err := handlers.CollectGarbage(false, handlers.DefaultGCGracePeriod)
+				if err != nil {
+					log.Printf("Error: garbage collection failed: %v\n", err)
+					continue
+				}
+				log.Printf("Garbage collection: deleted %d objects (%d bytes)\n", report.Deleted, report.FreedBytes)
+			}
+		}()
+	}
+
+	// LINK_CHECK_INTERVAL が設定されている場合は定期的にリンク切れを確認する
+	if interval, err := time.ParseDuration(os.Getenv("LINK_CHECK_INTERVAL")); err == nil && interval > 0 {
+		go func() {
+			for range time.Tick(interval) {
+				report, err := handlers.DefaultLinkChecker.Run(context.Background())
+				if err != nil {
+					log.Printf("Error: link check failed: %v\n", err)
+					continue
+				}
+				log.Printf("Link check: %d broken of %d links in %d items\n", report.Broken, report.Links, report.Items)
+			}
+		}()
+	}
+
+	// 検索用の情報が保存される前に作成されたアイテムについて、その情報を作成する
+	go func() {
+		report, err := handlers.BackfillItemIndexes()
+		if err != nil {
+			log.Printf("Error: backfill failed: %v\n", err)
+			return
+		}
+		log.Printf("Backfill: saved code snippets for %d items and fingerprints for %d items\n", report.Snippets, report.Fingerprints)
+	}()
+
+	// 予約公開のスケジューラ（PUBLISH_SCHEDULER_INTERVAL で間隔を変更できる、既定は1分）
+	publishInterval, err := time.ParseDuration(os.Getenv("PUBLISH_SCHEDULER_INTERVAL"))
+	if err != nil || publishInterval <= 0 {
+		publishInterval = time.Minute
+	}
+	go func() {
+		for range time.Tick(publishInterval) {
+			n, err := handlers.PublishScheduledItems(context.Background())
+			if err != nil {
+				log.Printf("Error: scheduled publishing failed: %v\n", err)
+				continue
+			}
+			if n > 0 {
+				log.Printf("Scheduled publishing: published %d items\n", n)
+			}
+		}
+	}()
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
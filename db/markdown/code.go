@@ -0,0 +1,104 @@
+package markdown
+
+import "strings"
+
+// CodeBlock はコードブロック（``` または ~~~ で囲まれた部分）
+// Line はコードブロックの開始行（1始まり）
+type CodeBlock struct {
+	Language string `json:"language"`
+	Code     string `json:"code"`
+	Line     int    `json:"line"`
+}
+
+// maxLanguageLength は言語名の最大の長さ
+const maxLanguageLength = 30
+
+// languageAliases は言語名の別名と正式な名前
+var languageAliases = map[string]string{
+	"js":      "javascript",
+	"jsx":     "javascript",
+	"ts":      "typescript",
+	"tsx":     "typescript",
+	"py":      "python",
+	"python3": "python",
+	"golang":  "go",
+	"sh":      "bash",
+	"shell":   "bash",
+	"zsh":     "bash",
+	"console": "bash",
+	"c++":     "cpp",
+	"cc":      "cpp",
+	"cs":      "csharp",
+	"c#":      "csharp",
+	"rb":      "ruby",
+	"rs":      "rust",
+	"kt":      "kotlin",
+	"yml":     "yaml",
+	"md":      "markdown",
+	"mysql":   "sql",
+	"htm":     "html",
+}
+
+// NormalizeLanguage はコードブロックの言語名を小文字にし、別名を正式な名前にそろえる
+// 言語名に使えない文字は取り除き、長すぎる名前は切り詰める
+func NormalizeLanguage(lang string) string {
+	lang = languagePattern.ReplaceAllString(strings.ToLower(strings.TrimSpace(lang)), "")
+	if len(lang) > maxLanguageLength {
+		lang = lang[:maxLanguageLength]
+	}
+	if alias, ok := languageAliases[lang]; ok {
+		return alias
+	}
+	return lang
+}
+
+// CodeBlocks は Markdown の中のコードブロックを出現順に返す
+// 引用やリストの中のコードブロックも含める
+func CodeBlocks(src string) []CodeBlock {
+	blocks := []CodeBlock{}
+	lines := splitLines(src)
+	for i := 0; i < len(lines); {
+		line := stripContainers(lines[i])
+		m := fencePattern.FindStringSubmatch(line)
+		if m == nil {
+			i++
+			continue
+		}
+
+		prefix := len(lines[i]) - len(line)
+		body := make([]string, 0, len(lines)-i)
+		for _, l := range lines[i:] {
+			body = append(body, trimContainerPrefix(l, prefix))
+		}
+		code, next := fencedCode(body, 0, m[1])
+		blocks = append(blocks, CodeBlock{Language: NormalizeLanguage(m[2]), Code: code, Line: i + 1})
+		i += next
+	}
+	return blocks
+}
+
+// stripContainers は行頭の引用記号とリストの記号・インデントを取り除く
+func stripContainers(line string) string {
+	for {
+		trimmed := strings.TrimLeft(line, " ")
+		switch {
+		case strings.HasPrefix(trimmed, ">"):
+			line = strings.TrimPrefix(trimmed[1:], " ")
+		case bulletPattern.MatchString(line):
+			line = bulletPattern.FindStringSubmatch(line)[3]
+		case orderedPattern.MatchString(line):
+			line = orderedPattern.FindStringSubmatch(line)[3]
+		default:
+			return trimmed
+		}
+	}
+}
+
+// trimContainerPrefix はコードブロックの各行から、最大 n バイトの引用記号・インデントを取り除く
+func trimContainerPrefix(line string, n int) string {
+	i := 0
+	for i < n && i < len(line) && (line[i] == ' ' || line[i] == '>') {
+		i++
+	}
+	return line[i:]
+}
@@ -0,0 +1,232 @@
+package markdown
+
+import (
+	"html"
+	"strings"
+)
+
+// inline は1行分の文字列をインライン要素（強調・コード・リンク・画像）に変換する
+// それ以外の文字はすべてエスケープする
+func (r *renderer) inline(s string) string {
+	if r.inlineDepth >= maxNesting {
+		return html.EscapeString(s)
+	}
+	r.inlineDepth++
+	defer func() { r.inlineDepth-- }()
+
+	// 閉じる記号が見つからなかった強調の記号（それより後ろから探しても見つからないため、探し直さない）
+	unclosed := map[string]bool{}
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte("\\`*_{}[]()#+-.!~<>|", s[i+1]) >= 0:
+			b.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+			continue
+
+		case c == '`':
+			ticks := countRun(s[i:], '`')
+			if end := strings.Index(s[i+ticks:], s[i:i+ticks]); end >= 0 {
+				code := strings.TrimSpace(s[i+ticks : i+ticks+end])
+				b.WriteString("<code>" + html.EscapeString(code) + "</code>")
+				i += ticks + end + ticks
+				continue
+			}
+			b.WriteString(s[i : i+ticks])
+			i += ticks
+			continue
+
+		case c == '!' && strings.HasPrefix(s[i:], "!["):
+			if alt, target, n, ok := parseLink(s[i+1:]); ok {
+				if u := r.safeURL(target); u != "" {
+					b.WriteString(`<img src="` + html.EscapeString(u) + `" alt="` + html.EscapeString(alt) + `">`)
+					i += 1 + n
+					continue
+				}
+			}
+
+		case c == '[':
+			if text, target, n, ok := parseLink(s[i:]); ok {
+				if u := r.safeURL(target); u != "" {
+					b.WriteString(linkTag(u) + r.inline(text) + "</a>")
+				} else {
+					b.WriteString(r.inline(text))
+				}
+				i += n
+				continue
+			}
+
+		case c == '<':
+			if end := strings.IndexByte(s[i:], '>'); end > 0 {
+				target := s[i+1 : i+end]
+				if !strings.ContainsAny(target, " \t<") && hasAllowedScheme(target) {
+					if u := r.safeURL(target); u != "" {
+						b.WriteString(linkTag(u) + html.EscapeString(target) + "</a>")
+						i += end + 1
+						continue
+					}
+				}
+			}
+
+		case c == '*' || c == '_' || c == '~':
+			if out, n, ok := r.emphasis(s, i, unclosed); ok {
+				b.WriteString(out)
+				i += n
+				continue
+			}
+		}
+
+		b.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+	return b.String()
+}
+
+func countRun(s string, c byte) int {
+	n := 0
+	for n < len(s) && s[n] == c {
+		n++
+	}
+	return n
+}
+
+// emphasis は **強調**・*斜体*・~~取り消し線~~ を変換し、読み進めたバイト数を返す
+// 単語の途中の _ は強調として扱わない（snake_case の識別子を崩さないため）
+// 閉じる記号が見つからなかった記号は unclosed に記録し、同じ行の後ろの位置では探さない
+func (r *renderer) emphasis(s string, i int, unclosed map[string]bool) (string, int, bool) {
+	c := s[i]
+	run := countRun(s[i:], c)
+	if c == '~' && run != 2 {
+		return "", 0, false
+	}
+	if c == '_' && i > 0 && isWordByte(s[i-1]) {
+		return "", 0, false
+	}
+	if run > 2 {
+		run = 2
+	}
+	delim := s[i : i+run]
+	start := i + run
+	if start >= len(s) || s[start] == ' ' || unclosed[delim] {
+		return "", 0, false
+	}
+	end := strings.Index(s[start:], delim)
+	for end == 0 || (end > 0 && s[start+end-1] == ' ') {
+		next := strings.Index(s[start+end+1:], delim)
+		if next < 0 {
+			end = -1
+			break
+		}
+		end += next + 1
+	}
+	if end < 0 {
+		unclosed[delim] = true
+		return "", 0, false
+	}
+	if c == '_' && start+end+run < len(s) && isWordByte(s[start+end+run]) {
+		return "", 0, false
+	}
+
+	inner := r.inline(s[start : start+end])
+	tag := "em"
+	switch {
+	case c == '~':
+		tag = "del"
+	case run == 2:
+		tag = "strong"
+	}
+	return "<" + tag + ">" + inner + "</" + tag + ">", run + end + run, true
+}
+
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+// parseLink は [テキスト](URL "タイトル") を読み取り、テキスト・URL・読み進めたバイト数を返す
+func parseLink(s string) (text, target string, n int, ok bool) {
+	depth := 0
+	closing := -1
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				closing = i
+			}
+		}
+		if closing >= 0 {
+			break
+		}
+	}
+	if closing < 0 || closing+1 >= len(s) || s[closing+1] != '(' {
+		return "", "", 0, false
+	}
+
+	depth = 0
+	for i := closing + 1; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				target = strings.TrimSpace(s[closing+2 : i])
+				// タイトルは使わない
+				if sp := strings.IndexAny(target, " \t"); sp >= 0 {
+					target = target[:sp]
+				}
+				target = strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+				return s[1:closing], target, i + 1, true
+			}
+		}
+	}
+	return "", "", 0, false
+}
+
+// hasAllowedScheme はURLが許可したスキームで始まるかどうかを返す
+func hasAllowedScheme(u string) bool {
+	lower := strings.ToLower(u)
+	for _, scheme := range allowedURLSchemes {
+		if strings.HasPrefix(lower, scheme+":") {
+			return true
+		}
+	}
+	return false
+}
+
+// safeURL は許可したスキームか相対URLのみを返す（javascript: などは空文字）
+func (r *renderer) safeURL(u string) string {
+	u = strings.TrimSpace(u)
+	if u == "" {
+		return ""
+	}
+	// スキームの判定の前に、ブラウザが無視する制御文字・空白を取り除く
+	u = strings.Map(func(c rune) rune {
+		if c < 0x20 || c == 0x7f {
+			return -1
+		}
+		return c
+	}, u)
+	if colon := strings.IndexByte(u, ':'); colon >= 0 {
+		if stop := strings.IndexAny(u, "/?#"); (stop < 0 || colon < stop) && !hasAllowedScheme(u) {
+			return ""
+		}
+	}
+	if r.opts.RewriteURL != nil {
+		u = r.opts.RewriteURL(u)
+	}
+	return u
+}
+
+// linkTag はリンクの開始タグを返す（外部サイトへのリンクは新しいタブで開き、参照元を送らない）
+func linkTag(u string) string {
+	if externalURLPattern.MatchString(u) {
+		return `<a href="` + html.EscapeString(u) + `" rel="nofollow noopener noreferrer" target="_blank">`
+	}
+	return `<a href="` + html.EscapeString(u) + `">`
+}
@@ -0,0 +1,295 @@
+// Package markdown はアイテムの本文の Markdown を安全な HTML に変換する
+// 本文中の HTML タグはすべてエスケープし、許可したタグ・スキームのリンクだけを出力する
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Heading は目次の項目
+type Heading struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	ID    string `json:"id"`
+}
+
+// Options は変換時の設定
+type Options struct {
+	// RewriteURL はリンク・画像のURLを書き換える（nil の場合は書き換えない）
+	// 空文字を返した場合はリンクにせず文字列として出力する
+	RewriteURL func(string) string
+}
+
+// Result は変換した HTML と、見出しから作成した目次
+type Result struct {
+	HTML string    `json:"html"`
+	TOC  []Heading `json:"toc"`
+}
+
+var (
+	headingPattern     = regexp.MustCompile(`^(#{1,6})(?:[ \t]+(.*?))?[ \t]*#*[ \t]*$`)
+	rulePattern        = regexp.MustCompile(`^ {0,3}(?:(?:-[ \t]*){3,}|(?:\*[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	bulletPattern      = regexp.MustCompile(`^( {0,3})([-*+])[ \t]+(.*)$`)
+	orderedPattern     = regexp.MustCompile(`^( {0,3})(\d{1,9})[.)][ \t]+(.*)$`)
+	fencePattern       = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})[ \t]*([^`\\s]*)")
+	languagePattern    = regexp.MustCompile(`[^a-z0-9+#_-]`)
+	quotePattern       = regexp.MustCompile(`^ {0,3}> ?(.*)$`)
+	tagPattern         = regexp.MustCompile(`<[^>]*>`)
+	allowedURLSchemes  = []string{"http", "https", "mailto"}
+	externalURLPattern = regexp.MustCompile(`^(?i)https?://`)
+)
+
+// maxNesting は引用・リスト・インライン要素を入れ子として扱う深さの上限
+// 深い入れ子は変換に入れ子の深さに比例した時間がかかるため、上限を超えた分は文字列として出力する
+const maxNesting = 32
+
+// renderer は1回の変換の状態（見出しのIDの重複を避けるために使う）
+type renderer struct {
+	opts        Options
+	toc         []Heading
+	ids         map[string]int
+	depth       int // 引用・リストの入れ子の深さ
+	inlineDepth int // インライン要素の入れ子の深さ
+}
+
+// Render は Markdown を HTML に変換し、目次を返す
+func Render(src string, opts Options) Result {
+	r := &renderer{opts: opts, toc: []Heading{}, ids: map[string]int{}}
+	var b strings.Builder
+	r.blocks(&b, splitLines(src))
+	return Result{HTML: b.String(), TOC: r.toc}
+}
+
+func splitLines(src string) []string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\t", "    ")
+	return strings.Split(src, "\n")
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+// startsBlock は段落を終わらせる行（見出し・区切り線・コード・引用・リスト）かどうかを返す
+func startsBlock(line string) bool {
+	return headingPattern.MatchString(strings.TrimLeft(line, " ")) || rulePattern.MatchString(line) || fencePattern.MatchString(line) || quotePattern.MatchString(line) ||
+		bulletPattern.MatchString(line) || orderedPattern.MatchString(line)
+}
+
+// blocks は行をブロック要素に変換する
+func (r *renderer) blocks(b *strings.Builder, lines []string) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimLeft(line, " ")
+		switch {
+		case isBlank(line):
+			i++
+
+		case fencePattern.MatchString(line):
+			m := fencePattern.FindStringSubmatch(line)
+			code, next := fencedCode(lines, i, m[1])
+			lang := NormalizeLanguage(m[2])
+			if lang != "" {
+				fmt.Fprintf(b, "<pre><code class=\"language-%s\">", lang)
+			} else {
+				b.WriteString("<pre><code>")
+			}
+			b.WriteString(html.EscapeString(code))
+			b.WriteString("</code></pre>\n")
+			i = next
+
+		case headingPattern.MatchString(trimmed):
+			m := headingPattern.FindStringSubmatch(trimmed)
+			r.heading(b, len(m[1]), m[2])
+			i++
+
+		case rulePattern.MatchString(line):
+			b.WriteString("<hr>\n")
+			i++
+
+		case r.depth < maxNesting && quotePattern.MatchString(line):
+			var quoted []string
+			for ; i < len(lines) && quotePattern.MatchString(lines[i]); i++ {
+				quoted = append(quoted, quotePattern.FindStringSubmatch(lines[i])[1])
+			}
+			b.WriteString("<blockquote>\n")
+			r.depth++
+			r.blocks(b, quoted)
+			r.depth--
+			b.WriteString("</blockquote>\n")
+
+		case r.depth < maxNesting && (bulletPattern.MatchString(line) || orderedPattern.MatchString(line)):
+			r.depth++
+			i = r.list(b, lines, i)
+			r.depth--
+
+		default:
+			var para []string
+			for ; i < len(lines) && !isBlank(lines[i]) && (len(para) == 0 || !startsBlock(lines[i])); i++ {
+				para = append(para, lines[i])
+			}
+			b.WriteString("<p>")
+			b.WriteString(r.paragraphInline(para))
+			b.WriteString("</p>\n")
+		}
+	}
+}
+
+// fencedCode はコードブロックの中身と、その次の行の位置を返す（閉じていない場合は最後まで）
+func fencedCode(lines []string, start int, fence string) (string, int) {
+	var code []string
+	for i := start + 1; i < len(lines); i++ {
+		t := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(t, fence[:3]) && strings.Trim(t, fence[:1]) == "" && len(t) >= len(fence) {
+			return strings.Join(code, "\n"), i + 1
+		}
+		code = append(code, lines[i])
+	}
+	return strings.Join(code, "\n"), len(lines)
+}
+
+// heading は見出しを出力し、目次に追加する
+func (r *renderer) heading(b *strings.Builder, level int, text string) {
+	inner := r.inline(strings.TrimSpace(text))
+	plain := strings.TrimSpace(html.UnescapeString(tagPattern.ReplaceAllString(inner, "")))
+	id := r.uniqueID(slugify(plain))
+	r.toc = append(r.toc, Heading{Level: level, Text: plain, ID: id})
+	fmt.Fprintf(b, "<h%d id=\"%s\">%s</h%d>\n", level, html.EscapeString(id), inner, level)
+}
+
+// slugify は見出しの文字列からアンカー用のIDを作る（日本語はそのまま残す）
+func slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, c := range strings.ToLower(s) {
+		switch {
+		case unicode.IsLetter(c) || unicode.IsDigit(c):
+			b.WriteRune(c)
+			dash = false
+		case unicode.IsSpace(c) || c == '-' || c == '_':
+			if !dash && b.Len() > 0 {
+				b.WriteByte('-')
+				dash = true
+			}
+		}
+	}
+	slug := strings.TrimSuffix(b.String(), "-")
+	if slug == "" {
+		return "section"
+	}
+	return slug
+}
+
+func (r *renderer) uniqueID(id string) string {
+	n := r.ids[id]
+	r.ids[id] = n + 1
+	if n == 0 {
+		return id
+	}
+	return fmt.Sprintf("%s-%d", id, n)
+}
+
+// list はリストを出力し、その次の行の位置を返す
+// 項目の2行目以降はインデントされた行を含め、入れ子のリストはインデントで判断する
+func (r *renderer) list(b *strings.Builder, lines []string, start int) int {
+	ordered := orderedPattern.MatchString(lines[start])
+	pattern := bulletPattern
+	if ordered {
+		pattern = orderedPattern
+		if n := orderedPattern.FindStringSubmatch(lines[start])[2]; n != "1" {
+			fmt.Fprintf(b, "<ol start=\"%s\">\n", strings.TrimLeft(n, "0"))
+		} else {
+			b.WriteString("<ol>\n")
+		}
+	} else {
+		b.WriteString("<ul>\n")
+	}
+
+	i := start
+	for i < len(lines) && pattern.MatchString(lines[i]) {
+		m := pattern.FindStringSubmatch(lines[i])
+		indent := len(m[1]) + 2
+		item := []string{m[3]}
+		loose := false
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			if isBlank(line) {
+				// 空行の後もインデントが続く場合は同じ項目の続き
+				if i+1 < len(lines) && leadingSpaces(lines[i+1]) >= indent {
+					item = append(item, "")
+					loose = true
+					continue
+				}
+				break
+			}
+			if leadingSpaces(line) >= indent {
+				item = append(item, line[indent:])
+				continue
+			}
+			if pattern.MatchString(line) || startsBlock(line) {
+				break
+			}
+			// インデントされていない続きの行は段落の続き
+			item = append(item, strings.TrimLeft(line, " "))
+		}
+
+		b.WriteString("<li>")
+		if loose {
+			b.WriteString("\n")
+			r.blocks(b, item)
+		} else {
+			// 詰めて書かれた項目は、先頭の段落を p で囲まずに出力する
+			text := 1
+			for text < len(item) && !startsBlock(item[text]) {
+				text++
+			}
+			b.WriteString(r.paragraphInline(item[:text]))
+			if text < len(item) {
+				b.WriteString("\n")
+				r.blocks(b, item[text:])
+			}
+		}
+		b.WriteString("</li>\n")
+
+		// 項目の間の空行は読み飛ばす
+		for i < len(lines) && isBlank(lines[i]) && i+1 < len(lines) && pattern.MatchString(lines[i+1]) {
+			i++
+		}
+	}
+
+	if ordered {
+		b.WriteString("</ol>\n")
+	} else {
+		b.WriteString("</ul>\n")
+	}
+	return i
+}
+
+func leadingSpaces(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// paragraphInline は段落の行を連結してインライン要素に変換する
+// 行末の2つ以上の空白またはバックスラッシュは改行にする
+func (r *renderer) paragraphInline(lines []string) string {
+	var b strings.Builder
+	for i, line := range lines {
+		hardBreak := strings.HasSuffix(line, "  ") || strings.HasSuffix(line, "\\")
+		line = strings.TrimSpace(line)
+		if hardBreak {
+			line = strings.TrimSuffix(line, "\\")
+		}
+		b.WriteString(r.inline(line))
+		if i < len(lines)-1 {
+			if hardBreak {
+				b.WriteString("<br>")
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
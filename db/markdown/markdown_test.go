@@ -0,0 +1,62 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// 入れ子や閉じない記号が大量に続く入力でも、入力の長さに比例する程度の時間で変換できること
+func TestRenderPathologicalInput(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{name: "nested blockquotes", src: strings.Repeat(">", 100000)},
+		{name: "nested blockquote lines", src: strings.Repeat(strings.Repeat(">", 200)+" a\n", 500)},
+		{name: "nested lists", src: strings.Repeat("- ", 50000)},
+		{name: "unclosed emphasis", src: strings.Repeat("*a ", 100000)},
+		{name: "unclosed strong", src: strings.Repeat("**a ", 100000)},
+		{name: "unclosed strikethrough", src: strings.Repeat("~~a ", 100000)},
+		{name: "nested emphasis", src: strings.Repeat("*_", 50000) + "a" + strings.Repeat("_*", 50000)},
+		{name: "nested links", src: strings.Repeat("[", 50000) + "a" + strings.Repeat("](b)", 50000)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			Render(tt.src, Options{})
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("Render took %v for %d bytes", elapsed, len(tt.src))
+			}
+		})
+	}
+}
+
+func TestRenderNestingLimit(t *testing.T) {
+	src := strings.Repeat(">", maxNesting+5) + " deep"
+	html := Render(src, Options{}).HTML
+	if got := strings.Count(html, "<blockquote>"); got != maxNesting {
+		t.Errorf("blockquotes = %d, want %d", got, maxNesting)
+	}
+	if !strings.Contains(html, "&gt;&gt;&gt;&gt;&gt; deep") {
+		t.Errorf("text beyond the nesting limit was not kept: %s", html)
+	}
+}
+
+func TestRenderEmphasis(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"*a* and *b*", "<p><em>a</em> and <em>b</em></p>\n"},
+		{"**a** *b", "<p><strong>a</strong> *b</p>\n"},
+		{"*a *b*", "<p><em>a *b</em></p>\n"},
+		{"snake_case_name", "<p>snake_case_name</p>\n"},
+		{"~~gone~~", "<p><del>gone</del></p>\n"},
+	}
+	for _, tt := range tests {
+		if got := Render(tt.src, Options{}).HTML; got != tt.want {
+			t.Errorf("Render(%q) = %q, want %q", tt.src, got, tt.want)
+		}
+	}
+}
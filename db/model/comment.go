@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+type Comment struct {
+	ID            string    `json:"id"`
+	ItemID        string    `json:"itemId"`
+	ParentID      string    `json:"parentId,omitempty"`
+	Body          string    `json:"body"`
+	CreatedBy     string    `json:"createdBy"`
+	CreatedByName string    `json:"createdByName"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+	// 返信が付いているコメントを削除した場合は本文を消して削除済みとして残す
+	Deleted bool      `json:"deleted"`
+	Replies []Comment `json:"replies,omitempty"`
+}
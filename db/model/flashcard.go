@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// FlashcardDeck はアイテムまたは章に紐づく暗記カードの束
+// アイテムに紐づく場合も、アイテムのカテゴリ・章を合わせて保存する
+type FlashcardDeck struct {
+	ID        string      `json:"id"`
+	CourseID  string      `json:"courseId"`
+	Title     string      `json:"title"`
+	ItemID    string      `json:"itemId,omitempty"`
+	Category  string      `json:"category"`
+	Chapter   string      `json:"chapter"`
+	CreatedBy string      `json:"createdBy"`
+	CreatedAt time.Time   `json:"createdAt"`
+	UpdatedAt time.Time   `json:"updatedAt"`
+	CardCount int         `json:"cardCount"`
+	DueCount  int         `json:"dueCount"` // 呼び出したユーザーが今日復習するカードの数
+	Cards     []Flashcard `json:"cards,omitempty"`
+}
+
+// Flashcard は暗記カード
+// DueDate・Repetitions・IntervalDays は呼び出したユーザーの復習の状態（未復習の場合 DueDate は nil）
+type Flashcard struct {
+	ID           string  `json:"id"`
+	DeckID       string  `json:"deckId"`
+	Front        string  `json:"front"`
+	Back         string  `json:"back"`
+	DueDate      *string `json:"dueDate,omitempty"`
+	Repetitions  int     `json:"repetitions"`
+	IntervalDays int     `json:"intervalDays"`
+}
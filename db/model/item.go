@@ -4,6 +4,7 @@ import "time"
 
 type Item struct {
 	ID            string    `json:"id"`
+	CourseID      string    `json:"courseId"`
 	Title         string    `json:"title"`
 	Content       string    `json:"content"`
 	Category      string    `json:"category"`
@@ -14,4 +15,41 @@ type Item struct {
 	CreatedByName string    `json:"createdByName"`
 	CreatedAt     time.Time `json:"createdAt"`
 	UpdatedAt     time.Time `json:"updatedAt"`
+	// 公開範囲（public/course/group/private）。course はアイテムのコースのメンバーのみが閲覧できる
+	Visibility string `json:"visibility"`
+	// 公開範囲が group の場合の対象グループのID
+	VisibilityGroup string `json:"visibilityGroup,omitempty"`
+	// 公開状態（draft/in_review/rejected/scheduled/published/archived）
+	Status string `json:"status"`
+	// 管理者が非表示にしたかどうか（非表示のアイテムは作成者のみ閲覧できる）
+	Hidden bool `json:"hidden"`
+	// 予約公開の日時（UTC）
+	PublishAt *time.Time `json:"publishAt,omitempty"`
+	// いいね・ブックマークの数と、呼び出したユーザーが付けているかどうか
+	LikeCount     int  `json:"likeCount"`
+	BookmarkCount int  `json:"bookmarkCount"`
+	Liked         bool `json:"liked"`
+	Bookmarked    bool `json:"bookmarked"`
+	// 評価（1〜5）の平均と件数、呼び出したユーザーの評価（未評価は0）
+	RatingAvg   float64 `json:"ratingAvg"`
+	RatingCount int     `json:"ratingCount"`
+	MyRating    int     `json:"myRating"`
+	// 呼び出したユーザーが学習を完了しているかどうか
+	Completed bool `json:"completed"`
+	// 削除済みを除いたコメントの数
+	CommentCount int `json:"commentCount"`
+	// 本文（Markdown）を変換した HTML と目次（要求された場合のみ）
+	ContentHTML string    `json:"contentHtml,omitempty"`
+	TOC         []Heading `json:"toc,omitempty"`
+	// 自由に付けられるタグ（更新時に nil の場合は変更しない）
+	Tags []string `json:"tags"`
+	// サムネイルのサイズ名（small/medium/large）とURL
+	Thumbnails map[string]string `json:"thumbnails,omitempty"`
+}
+
+// Heading は本文の目次の項目
+type Heading struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	ID    string `json:"id"`
 }
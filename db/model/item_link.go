@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// ItemLink はアイテム間の関係（ItemID から見た TargetID との関係）
+// prerequisite: TargetID を先に学習する、related: 関連する、supersedes: ItemID が TargetID を置き換える
+type ItemLink struct {
+	ItemID      string    `json:"itemId"`
+	TargetID    string    `json:"targetId"`
+	Type        string    `json:"type"`
+	SourceTitle string    `json:"sourceTitle,omitempty"`
+	TargetTitle string    `json:"targetTitle,omitempty"`
+	CreatedBy   string    `json:"createdBy"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+type LearningPath struct {
+	ID          string    `json:"id"`
+	CourseID    string    `json:"courseId"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	CreatedBy   string    `json:"createdBy"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	// 順番に並べたアイテムのID
+	ItemIDs []string `json:"itemIds"`
+	// 呼び出したユーザーが完了したアイテムの数
+	CompletedCount int `json:"completedCount"`
+	// 単体で取得した場合のみ、閲覧できるアイテムを順番に設定する
+	Items []Item `json:"items,omitempty"`
+}
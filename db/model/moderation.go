@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// ItemReport はアイテムへの通報
+// Reason: inappropriate / incorrect / spam / other、Status: open / resolved / dismissed
+type ItemReport struct {
+	ID         string     `json:"id"`
+	ItemID     string     `json:"itemId"`
+	Reporter   string     `json:"reporter"`
+	Reason     string     `json:"reason"`
+	Comment    string     `json:"comment"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ResolvedBy string     `json:"resolvedBy,omitempty"`
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty"`
+}
+
+// ModerationLogEntry は管理者の対応の記録（アイテムを削除しても残す）
+// Action: hide / restore / dismiss、Reports は対応した通報の件数
+type ModerationLogEntry struct {
+	ID        string    `json:"id"`
+	ItemID    string    `json:"itemId"`
+	CourseID  string    `json:"courseId"`
+	Moderator string    `json:"moderator"`
+	Action    string    `json:"action"`
+	Note      string    `json:"note"`
+	Reports   int64     `json:"reports"`
+	CreatedAt time.Time `json:"createdAt"`
+}
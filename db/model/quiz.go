@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// QuizQuestion はアイテムに付けた小テストの問題
+// 正解（CorrectChoice・AcceptedAnswers）は問題を編集できるユーザーにのみ返す
+type QuizQuestion struct {
+	ID              string   `json:"id"`
+	ItemID          string   `json:"itemId"`
+	Kind            string   `json:"kind"` // choice: 選択式、short: 記述式
+	Prompt          string   `json:"prompt"`
+	Choices         []string `json:"choices,omitempty"`
+	Points          int      `json:"points"`
+	CorrectChoice   *int     `json:"correctChoice,omitempty"`
+	AcceptedAnswers []string `json:"acceptedAnswers,omitempty"`
+}
+
+// QuizResult は問題ごとの採点結果
+type QuizResult struct {
+	QuestionID string `json:"questionId"`
+	Correct    bool   `json:"correct"`
+	Points     int    `json:"points"`
+}
+
+// QuizAttempt はユーザーの小テストの解答1回分の結果
+type QuizAttempt struct {
+	ID         string       `json:"id"`
+	ItemID     string       `json:"itemId"`
+	UserEmail  string       `json:"userEmail"`
+	Score      int          `json:"score"`
+	MaxScore   int          `json:"maxScore"`
+	Percentage float64      `json:"percentage"`
+	Passed     bool         `json:"passed"`
+	CreatedAt  time.Time    `json:"createdAt"`
+	Results    []QuizResult `json:"results,omitempty"`
+}
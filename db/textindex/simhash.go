@@ -0,0 +1,57 @@
+package textindex
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"strings"
+)
+
+// ShingleSize はsimhashで使う連続したトークンの数
+const ShingleSize = 3
+
+// Shingles は連続する size 個のトークンを1つにまとめた語のリストを返す
+// トークンが size 個に満たない場合は全体を1つにまとめる
+func Shingles(tokens []string, size int) []string {
+	if len(tokens) == 0 {
+		return nil
+	}
+	if len(tokens) <= size {
+		return []string{strings.Join(tokens, " ")}
+	}
+	shingles := make([]string, 0, len(tokens)-size+1)
+	for i := 0; i+size <= len(tokens); i++ {
+		shingles = append(shingles, strings.Join(tokens[i:i+size], " "))
+	}
+	return shingles
+}
+
+// Simhash は語のリストから64ビットのsimhashを求める
+// 内容の近い文章ほど、ハミング距離の小さいハッシュになる
+func Simhash(features []string) uint64 {
+	var weights [64]int
+	for _, f := range features {
+		h := fnv.New64a()
+		h.Write([]byte(f))
+		sum := h.Sum64()
+		for i := 0; i < 64; i++ {
+			if sum&(1<<uint(i)) != 0 {
+				weights[i]++
+			} else {
+				weights[i]--
+			}
+		}
+	}
+
+	var hash uint64
+	for i, w := range weights {
+		if w > 0 {
+			hash |= 1 << uint(i)
+		}
+	}
+	return hash
+}
+
+// HammingDistance は2つのハッシュで異なるビットの数を返す
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
@@ -0,0 +1,93 @@
+// Package textindex は外部のサービスを使わずに、文章の類似度を求めるための処理をまとめたもの
+package textindex
+
+import (
+	"math"
+	"sort"
+)
+
+// Vector は特徴語ごとの重み（疎ベクトル）
+type Vector map[string]float64
+
+// Cosine は2つのベクトルのコサイン類似度を返す（どちらかが空の場合は0）
+func (v Vector) Cosine(o Vector) float64 {
+	if len(v) > len(o) {
+		v, o = o, v
+	}
+	var dot, nv, no float64
+	for term, w := range v {
+		dot += w * o[term]
+		nv += w * w
+	}
+	for _, w := range o {
+		no += w * w
+	}
+	if nv == 0 || no == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(nv*no)
+}
+
+// Top は重みの大きい順に n 個の特徴語を返す
+func (v Vector) Top(n int) []string {
+	terms := make([]string, 0, len(v))
+	for term := range v {
+		terms = append(terms, term)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if v[terms[i]] != v[terms[j]] {
+			return v[terms[i]] > v[terms[j]]
+		}
+		return terms[i] < terms[j]
+	})
+	if len(terms) > n {
+		terms = terms[:n]
+	}
+	return terms
+}
+
+// Corpus は文書の集合での特徴語ごとの出現文書数
+type Corpus struct {
+	docs int
+	df   map[string]int
+}
+
+func NewCorpus() *Corpus {
+	return &Corpus{df: map[string]int{}}
+}
+
+// Add は文書のトークンをコーパスに加える
+func (c *Corpus) Add(tokens []string) {
+	c.docs++
+	seen := map[string]bool{}
+	for _, t := range tokens {
+		if !seen[t] {
+			seen[t] = true
+			c.df[t]++
+		}
+	}
+}
+
+// Len はコーパスの文書数を返す
+func (c *Corpus) Len() int {
+	return c.docs
+}
+
+// IDF は特徴語の逆文書頻度を返す（平滑化しているため、コーパスにない語でも正の値になる）
+func (c *Corpus) IDF(term string) float64 {
+	return math.Log(float64(c.docs+1)/float64(c.df[term]+1)) + 1
+}
+
+// Vector はトークンを TF-IDF で重み付けしたベクトルを返す
+// TF は文書の長さの影響を抑えるため 1 + log(出現回数) を使う
+func (c *Corpus) Vector(tokens []string) Vector {
+	counts := map[string]int{}
+	for _, t := range tokens {
+		counts[t]++
+	}
+	v := make(Vector, len(counts))
+	for term, n := range counts {
+		v[term] = (1 + math.Log(float64(n))) * c.IDF(term)
+	}
+	return v
+}
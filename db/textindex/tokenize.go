@@ -0,0 +1,81 @@
+package textindex
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stopWords は英語の文章で頻出し、特徴にならない単語
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true, "you": true,
+	"with": true, "this": true, "that": true, "from": true, "have": true, "was": true, "were": true,
+	"will": true, "can": true, "its": true, "into": true, "than": true, "then": true, "there": true,
+	"their": true, "they": true, "them": true, "what": true, "when": true, "which": true, "who": true,
+	"how": true, "all": true, "any": true, "each": true, "our": true, "your": true, "about": true,
+}
+
+// scriptOf は文字の種類（英数字・漢字・カタカナ・ひらがな）を返す
+// 分かち書きされない日本語は、同じ種類の文字が続く範囲を単位にしてn-gramに分割する
+func scriptOf(r rune) rune {
+	switch {
+	case unicode.Is(unicode.Han, r) || r == '々':
+		return 'h'
+	case unicode.Is(unicode.Katakana, r) || r == 'ー':
+		return 'k'
+	case unicode.Is(unicode.Hiragana, r):
+		return 'r'
+	case unicode.IsLetter(r) || unicode.IsDigit(r):
+		return 'a'
+	}
+	return 0
+}
+
+// Tokenize は文章を特徴語に分割する
+// 英数字は2文字以上の単語（ストップワードを除く）、漢字・カタカナは文字bigramにする
+// 2〜8文字のカタカナ語と2〜4文字の漢字語はそのままの形も加える
+// ひらがなは助詞・活用語尾が大半のため使わない
+func Tokenize(s string) []string {
+	var tokens []string
+	runes := []rune(strings.ToLower(s))
+	for start := 0; start < len(runes); {
+		script := scriptOf(runes[start])
+		end := start + 1
+		for end < len(runes) && scriptOf(runes[end]) == script {
+			end++
+		}
+		run := runes[start:end]
+		start = end
+
+		switch script {
+		case 'a':
+			if word := string(run); len(run) >= 2 && !stopWords[word] {
+				tokens = append(tokens, word)
+			}
+		case 'h', 'k':
+			if len(run) == 1 {
+				if script == 'h' {
+					tokens = append(tokens, string(run))
+				}
+				continue
+			}
+			for i := 0; i+1 < len(run); i++ {
+				tokens = append(tokens, string(run[i:i+2]))
+			}
+			if len(run) > 2 && ((script == 'k' && len(run) <= 8) || (script == 'h' && len(run) <= 4)) {
+				tokens = append(tokens, string(run))
+			}
+		}
+	}
+	return tokens
+}
+
+// IsWord はトークンが単語として読める（bigramの断片ではない）かどうかを返す
+// 英数字の単語、3文字以上のカタカナ語・漢字語を単語とみなす
+func IsWord(token string) bool {
+	runes := []rune(token)
+	if len(runes) == 0 {
+		return false
+	}
+	script := scriptOf(runes[0])
+	return script == 'a' || len(runes) > 2
+}
@@ -0,0 +1,100 @@
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+)
+
+// Sizes はサムネイルの名前と長辺のピクセル数
+var Sizes = map[string]int{
+	"small":  160,
+	"medium": 320,
+	"large":  640,
+}
+
+// MaxPixels はサムネイルを作成する画像の最大の画素数
+// ファイルサイズが小さくても巨大なキャンバスを宣言した画像は、展開するとメモリを使い果たすため対象外にする
+const MaxPixels = 40_000_000
+
+// ErrTooLarge は画像の画素数が MaxPixels を超える場合のエラー
+var ErrTooLarge = errors.New("image is too large to generate thumbnails")
+
+// Generate は画像を読み込み、Sizes の各サイズに縮小した JPEG データを返す
+// 展開する前にヘッダーで画像のサイズを確認し、MaxPixels を超える場合は ErrTooLarge を返す
+func Generate(r io.Reader) (map[string][]byte, error) {
+	// ヘッダーを読んだ分は、展開時にもう一度読めるように保持しておく
+	var header bytes.Buffer
+	config, _, err := image.DecodeConfig(io.TeeReader(r, &header))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > MaxPixels {
+		return nil, ErrTooLarge
+	}
+
+	src, _, err := image.Decode(io.MultiReader(&header, r))
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnails := make(map[string][]byte, len(Sizes))
+	for name, size := range Sizes {
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, Resize(src, size), &jpeg.Options{Quality: 85}); err != nil {
+			return nil, err
+		}
+		thumbnails[name] = buf.Bytes()
+	}
+	return thumbnails, nil
+}
+
+// Resize は縦横比を保ったまま長辺が maxSize になるよう縮小する
+// 元画像の方が小さい場合は拡大せずそのままのサイズでコピーする
+func Resize(src image.Image, maxSize int) image.Image {
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	dw, dh := sw, sh
+	if sw > maxSize || sh > maxSize {
+		if sw >= sh {
+			dw, dh = maxSize, max(1, sh*maxSize/sw)
+		} else {
+			dw, dh = max(1, sw*maxSize/sh), maxSize
+		}
+	}
+
+	// 面積平均法: 出力ピクセルに対応する元画像の領域を平均する
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0 := b.Min.Y + y*sh/dh
+		y1 := max(y0+1, b.Min.Y+(y+1)*sh/dh)
+		for x := 0; x < dw; x++ {
+			x0 := b.Min.X + x*sw/dw
+			x1 := max(x0+1, b.Min.X+(x+1)*sw/dw)
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r += uint64(pr)
+					g += uint64(pg)
+					bl += uint64(pb)
+					a += uint64(pa)
+					n++
+				}
+			}
+			// JPEG は透過を持てないため、透明部分は白で埋める
+			white := 0xffff - a/n
+			i := dst.PixOffset(x, y)
+			dst.Pix[i+0] = uint8((r/n + white) >> 8)
+			dst.Pix[i+1] = uint8((g/n + white) >> 8)
+			dst.Pix[i+2] = uint8((bl/n + white) >> 8)
+			dst.Pix[i+3] = 0xff
+		}
+	}
+	return dst
+}
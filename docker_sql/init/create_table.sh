@@ -3,6 +3,7 @@
 CMD_MYSQL="mysql -u${MYSQL_USER} -p${MYSQL_PASSWORD} ${MYSQL_DATABASE}"
 $CMD_MYSQL -e "CREATE TABLE items (
   id INT AUTO_INCREMENT PRIMARY KEY,
+  courseId VARCHAR(26) NOT NULL,
   title VARCHAR(255) NOT NULL,
   content TEXT,
   category VARCHAR(50),
@@ -10,8 +11,356 @@ $CMD_MYSQL -e "CREATE TABLE items (
   file VARCHAR(255),
   createdBy VARCHAR(50) NOT NULL,
   createdByName VARCHAR(50),
+  visibility VARCHAR(20) NOT NULL DEFAULT 'public',
+  visibilityGroup VARCHAR(26) NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'published',
+  publishAt DATETIME NULL,
+  ratingAvg DECIMAL(3,2) NOT NULL DEFAULT 0,
+  ratingCount INT NOT NULL DEFAULT 0,
+  hidden BOOLEAN NOT NULL DEFAULT FALSE,
   createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-  updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+  updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  INDEX (courseId)
 );
 "
 
+$CMD_MYSQL -e "CREATE TABLE courses (
+  id VARCHAR(26) PRIMARY KEY,
+  name VARCHAR(100) NOT NULL,
+  createdBy VARCHAR(50) NOT NULL,
+  createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE course_members (
+  courseId VARCHAR(26) NOT NULL,
+  userEmail VARCHAR(50) NOT NULL,
+  role VARCHAR(20) NOT NULL DEFAULT 'student',
+  PRIMARY KEY (courseId, userEmail),
+  INDEX (userEmail)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE categories (
+  courseId VARCHAR(26) NOT NULL,
+  Name VARCHAR(50) NOT NULL,
+  PRIMARY KEY (courseId, Name)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE chapters (
+  courseId VARCHAR(26) NOT NULL,
+  Name VARCHAR(50) NOT NULL,
+  PRIMARY KEY (courseId, Name)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE thumbnails (
+  itemId VARCHAR(26) NOT NULL,
+  size VARCHAR(20) NOT NULL,
+  objectKey VARCHAR(255) NOT NULL,
+  PRIMARY KEY (itemId, size)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE item_reviews (
+  id VARCHAR(26) PRIMARY KEY,
+  itemId VARCHAR(26) NOT NULL,
+  reviewer VARCHAR(50) NOT NULL,
+  decision VARCHAR(20) NOT NULL,
+  comment TEXT,
+  createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  INDEX (itemId)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE notifications (
+  id VARCHAR(26) PRIMARY KEY,
+  userEmail VARCHAR(50) NOT NULL,
+  itemId VARCHAR(26),
+  message TEXT NOT NULL,
+  isRead BOOLEAN NOT NULL DEFAULT FALSE,
+  createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  INDEX (userEmail, createdAt)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE user_groups (
+  id VARCHAR(26) PRIMARY KEY,
+  name VARCHAR(100) NOT NULL,
+  createdBy VARCHAR(50) NOT NULL,
+  createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE user_group_members (
+  groupId VARCHAR(26) NOT NULL,
+  userEmail VARCHAR(50) NOT NULL,
+  PRIMARY KEY (groupId, userEmail),
+  INDEX (userEmail)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE item_likes (
+  itemId VARCHAR(26) NOT NULL,
+  userEmail VARCHAR(50) NOT NULL,
+  createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (itemId, userEmail),
+  INDEX (userEmail)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE item_bookmarks (
+  itemId VARCHAR(26) NOT NULL,
+  userEmail VARCHAR(50) NOT NULL,
+  createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (itemId, userEmail),
+  INDEX (userEmail)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE comments (
+  id VARCHAR(26) PRIMARY KEY,
+  itemId VARCHAR(26) NOT NULL,
+  parentId VARCHAR(26) NULL,
+  body TEXT NOT NULL,
+  createdBy VARCHAR(50) NOT NULL,
+  createdByName VARCHAR(50),
+  deleted BOOLEAN NOT NULL DEFAULT FALSE,
+  createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  INDEX (itemId, parentId, createdAt),
+  INDEX (parentId)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE item_ratings (
+  itemId VARCHAR(26) NOT NULL,
+  userEmail VARCHAR(50) NOT NULL,
+  score TINYINT NOT NULL,
+  createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (itemId, userEmail),
+  CHECK (score BETWEEN 1 AND 5)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE item_view_marks (
+  itemId VARCHAR(26) NOT NULL,
+  viewerKey VARCHAR(80) NOT NULL,
+  lastViewedAt DATETIME NOT NULL,
+  PRIMARY KEY (itemId, viewerKey)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE item_view_daily (
+  itemId VARCHAR(26) NOT NULL,
+  day DATE NOT NULL,
+  views INT NOT NULL DEFAULT 0,
+  PRIMARY KEY (itemId, day),
+  INDEX (day)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE item_progress (
+  userEmail VARCHAR(50) NOT NULL,
+  itemId VARCHAR(26) NOT NULL,
+  completedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (userEmail, itemId),
+  INDEX (itemId)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE learning_paths (
+  id VARCHAR(26) PRIMARY KEY,
+  courseId VARCHAR(26) NOT NULL,
+  title VARCHAR(255) NOT NULL,
+  description TEXT,
+  createdBy VARCHAR(50) NOT NULL,
+  createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  INDEX (courseId)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE learning_path_items (
+  pathId VARCHAR(26) NOT NULL,
+  itemId VARCHAR(26) NOT NULL,
+  position INT NOT NULL,
+  PRIMARY KEY (pathId, position),
+  UNIQUE (pathId, itemId),
+  INDEX (itemId)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE item_links (
+  itemId VARCHAR(26) NOT NULL,
+  targetId VARCHAR(26) NOT NULL,
+  linkType VARCHAR(20) NOT NULL,
+  createdBy VARCHAR(50) NOT NULL,
+  createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (itemId, targetId, linkType),
+  INDEX (targetId)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE quiz_questions (
+  id VARCHAR(26) PRIMARY KEY,
+  itemId VARCHAR(26) NOT NULL,
+  position INT NOT NULL,
+  kind VARCHAR(10) NOT NULL,
+  prompt TEXT NOT NULL,
+  choices TEXT NOT NULL,
+  points INT NOT NULL DEFAULT 1,
+  correctChoice INT,
+  acceptedAnswers TEXT NOT NULL,
+  INDEX (itemId, position)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE quiz_attempts (
+  id VARCHAR(26) PRIMARY KEY,
+  itemId VARCHAR(26) NOT NULL,
+  userEmail VARCHAR(50) NOT NULL,
+  score INT NOT NULL,
+  maxScore INT NOT NULL,
+  passed BOOLEAN NOT NULL,
+  results TEXT NOT NULL,
+  createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  INDEX (itemId, userEmail),
+  INDEX (userEmail)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE flashcard_decks (
+  id VARCHAR(26) PRIMARY KEY,
+  courseId VARCHAR(26) NOT NULL,
+  title VARCHAR(255) NOT NULL,
+  itemId VARCHAR(26),
+  category VARCHAR(50) NOT NULL,
+  chapter VARCHAR(50) NOT NULL,
+  createdBy VARCHAR(50) NOT NULL,
+  createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  INDEX (courseId, category, chapter),
+  INDEX (itemId)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE flashcards (
+  id VARCHAR(26) PRIMARY KEY,
+  deckId VARCHAR(26) NOT NULL,
+  position INT NOT NULL,
+  front TEXT NOT NULL,
+  back TEXT NOT NULL,
+  INDEX (deckId, position)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE flashcard_reviews (
+  userEmail VARCHAR(50) NOT NULL,
+  cardId VARCHAR(26) NOT NULL,
+  easiness DOUBLE NOT NULL DEFAULT 2.5,
+  repetitions INT NOT NULL DEFAULT 0,
+  intervalDays INT NOT NULL DEFAULT 0,
+  dueDate DATE NOT NULL,
+  lastQuality INT NOT NULL,
+  reviewedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (userEmail, cardId),
+  INDEX (cardId),
+  INDEX (userEmail, dueDate)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE tags (
+  id VARCHAR(26) PRIMARY KEY,
+  courseId VARCHAR(26) NOT NULL,
+  name VARCHAR(50) NOT NULL,
+  UNIQUE (courseId, name)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE item_tags (
+  itemId VARCHAR(26) NOT NULL,
+  tagId VARCHAR(26) NOT NULL,
+  PRIMARY KEY (itemId, tagId),
+  INDEX (tagId)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE item_related (
+  itemId VARCHAR(26) NOT NULL,
+  relatedId VARCHAR(26) NOT NULL,
+  score DOUBLE NOT NULL,
+  PRIMARY KEY (itemId, relatedId),
+  INDEX (relatedId),
+  INDEX (itemId, score)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE item_related_computed (
+  itemId VARCHAR(26) PRIMARY KEY,
+  computedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE item_fingerprints (
+  itemId VARCHAR(26) PRIMARY KEY,
+  simhash BIGINT UNSIGNED,
+  fileHash VARCHAR(64) NOT NULL DEFAULT '',
+  INDEX (fileHash)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE item_snippets (
+  itemId VARCHAR(26) NOT NULL,
+  position INT NOT NULL,
+  language VARCHAR(30) NOT NULL,
+  code MEDIUMTEXT NOT NULL,
+  line INT NOT NULL,
+  PRIMARY KEY (itemId, position),
+  INDEX (language, itemId)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE link_checks (
+  itemId VARCHAR(26) NOT NULL,
+  urlHash CHAR(64) NOT NULL,
+  url TEXT NOT NULL,
+  status INT NOT NULL,
+  error VARCHAR(255) NOT NULL DEFAULT '',
+  broken BOOLEAN NOT NULL,
+  checkedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (itemId, urlHash),
+  INDEX (broken)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE item_reports (
+  id VARCHAR(26) PRIMARY KEY,
+  itemId VARCHAR(26) NOT NULL,
+  reporter VARCHAR(255) NOT NULL,
+  reason VARCHAR(20) NOT NULL,
+  comment TEXT NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'open',
+  createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  resolvedBy VARCHAR(255),
+  resolvedAt TIMESTAMP NULL,
+  INDEX (status, createdAt),
+  INDEX (itemId, reporter)
+);
+"
+
+$CMD_MYSQL -e "CREATE TABLE moderation_log (
+  id VARCHAR(26) PRIMARY KEY,
+  itemId VARCHAR(26) NOT NULL,
+  courseId VARCHAR(26) NOT NULL,
+  moderator VARCHAR(255) NOT NULL,
+  action VARCHAR(20) NOT NULL,
+  note TEXT NOT NULL,
+  reports INT NOT NULL DEFAULT 0,
+  createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  INDEX (itemId),
+  INDEX (createdAt)
+);
+"